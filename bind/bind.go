@@ -24,6 +24,8 @@ import (
 	"bytes"
 	"fmt"
 	"go/format"
+	"sort"
+	"strings"
 	tmpl "text/template"
 
 	"github.com/airbloc/solgen/bind/language"
@@ -32,6 +34,8 @@ import (
 	"github.com/airbloc/solgen/bind/template/golang"
 	"github.com/airbloc/solgen/deployment"
 	"github.com/airbloc/solgen/utils"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
 )
 
 type Mode string
@@ -46,13 +50,70 @@ var Modes = []Mode{
 	Manager,
 }
 
-func getInternalFuncs(mode Mode, lang language.Language) map[string]interface{} {
+// ModeError is returned by Bind when rendering the binds of a mode fails.
+type ModeError struct {
+	Mode Mode
+	Err  error
+}
+
+func (e *ModeError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Mode, e.Err)
+}
+
+// ParseMode resolves a mode name, failing if it is not one of Modes.
+func ParseMode(name string) (Mode, error) {
+	names := make([]string, len(Modes))
+	for i, mode := range Modes {
+		if string(mode) == name {
+			return mode, nil
+		}
+		names[i] = string(mode)
+	}
+	return "", fmt.Errorf("unknown mode %q (supported: %s)", name, strings.Join(names, ", "))
+}
+
+// Languages returns the languages having templates of every mode, sorted by name.
+func Languages() []language.Language {
+	var langs []language.Language
+	for _, lang := range language.Languages() {
+		supported := true
+		for _, mode := range Modes {
+			supported = supported && getTemplate(mode, lang) != ""
+		}
+		if supported {
+			langs = append(langs, lang)
+		}
+	}
+	return langs
+}
+
+// ParseLanguage resolves a language name, failing if it is not one of Languages.
+func ParseLanguage(name string) (language.Language, error) {
+	langs := Languages()
+	names := make([]string, len(langs))
+	for i, lang := range langs {
+		if string(lang) == strings.ToLower(name) {
+			return lang, nil
+		}
+		names[i] = string(lang)
+	}
+	return "", fmt.Errorf("unsupported language %q (supported: %s)", name, strings.Join(names, ", "))
+}
+
+func getInternalFuncs(mode Mode, lang language.Language, types language.TypeMap) map[string]interface{} {
+	bindType := func(kind abi.Type, structs map[string]*template.Struct) string {
+		return language.BindType[lang](kind, structs, types)
+	}
+	bindTopicType := func(kind abi.Type, structs map[string]*template.Struct) string {
+		return language.BindTopicType[lang](kind, structs, types)
+	}
+
 	switch mode {
 	case Contract:
 		return map[string]interface{}{
 			// from lang package
-			"bindtype":      language.BindType[lang],
-			"bindtopictype": language.BindTopicType[lang],
+			"bindtype":      bindType,
+			"bindtopictype": bindTopicType,
 			"namedtype":     language.NamedType[lang],
 
 			// from utils package
@@ -64,8 +125,8 @@ func getInternalFuncs(mode Mode, lang language.Language) map[string]interface{}
 	case Manager:
 		return map[string]interface{}{
 			// from lang package
-			"bindtype":      language.BindType[lang],
-			"bindtopictype": language.BindTopicType[lang],
+			"bindtype":      bindType,
+			"bindtopictype": bindTopicType,
 
 			// from utils package
 			"decapitalise": utils.Decapitalise,
@@ -90,27 +151,64 @@ func getTemplate(mode Mode, lang language.Language) string {
 	return ""
 }
 
+// addTypeImports adds the imports of the type map to the imports of a mode.
+// A package name already imported from another path is an error, rather than silently replaced.
+func addTypeImports(imports, typeImports map[string]string) (map[string]string, error) {
+	names := make([]string, 0, len(typeImports))
+	for name := range typeImports {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	merged := platform.MergeImports(imports)
+	for _, name := range names {
+		if other, ok := merged[name]; ok && other != typeImports[name] {
+			return nil, fmt.Errorf("type map imports %s as %s, which is already imported from %s", typeImports[name], name, other)
+		}
+		merged[name] = typeImports[name]
+	}
+	return merged, nil
+}
+
 func Bind(name string, deployment deployment.Deployment, opt Option) (map[Mode][]byte, error) {
 	contract, err := getContract(deployment, opt.Customs, opt.Language)
 	if err != nil {
 		return nil, err
 	}
 	contract.Type = utils.Capitalise(name)
+	types, typeImports, err := opt.Customs.TypeMap.bind(opt.Language)
+	if err != nil {
+		return nil, err
+	}
+
+	modes := opt.Modes
+	if len(modes) == 0 {
+		modes = Modes
+	}
 
 	codes := make(map[Mode][]byte)
-	for _, mode := range Modes {
-		data := &template.Data{
-			Imports:  platform.MergeImports(platform.Imports[opt.Platform], opt.Customs.Imports),
-			Contract: contract,
-			Package:  string(mode),
-		}
+	for _, mode := range modes {
+		imports := platform.MergeImports(platform.Imports[opt.Platform], opt.Customs.Imports)
 		if mode == Manager {
-			data.Imports = platform.ManagerImports(opt.Platform)
+			imports = platform.ManagerImports(opt.Platform)
+			if opt.ContractsImport != "" {
+				imports[opt.packageOf(Contract)] = opt.ContractsImport
+			}
+		}
+		imports, err := addTypeImports(imports, typeImports)
+		if err != nil {
+			return nil, &ModeError{Mode: mode, Err: err}
 		}
 
-		code, err := bind(mode, data, opt)
+		data := &template.Data{
+			Imports:          imports,
+			Contract:         contract,
+			Package:          opt.packageOf(mode),
+			ContractsPackage: opt.packageOf(Contract),
+		}
+		code, err := bind(mode, data, types, opt)
 		if err != nil {
-			return nil, err
+			return nil, &ModeError{Mode: mode, Err: err}
 		}
 		codes[mode] = code
 	}
@@ -121,11 +219,15 @@ func Bind(name string, deployment deployment.Deployment, opt Option) (map[Mode][
 func bind(
 	mode Mode,
 	data *template.Data,
+	types language.TypeMap,
 	opt Option,
 ) ([]byte, error) {
 	buffer := new(bytes.Buffer)
-	functions := getInternalFuncs(mode, opt.Language)
+	functions := getInternalFuncs(mode, opt.Language, types)
 	templates := getTemplate(mode, opt.Language)
+	if templates == "" {
+		return nil, fmt.Errorf("%s binding is not supported for %s yet", opt.Language, mode)
+	}
 	t := tmpl.Must(tmpl.New(string(mode)).Funcs(functions).Parse(templates))
 	if err := t.ExecuteTemplate(buffer, string(mode), data); err != nil {
 		return nil, err
@@ -146,9 +248,5 @@ func bind(
 		code = buffer.Bytes()
 	}
 
-	code = bytes.ReplaceAll(code, []byte("[8]byte"), []byte("types.ID"))
-	code = bytes.ReplaceAll(code, []byte("[32]byte"), []byte("common.Hash"))
-	code = bytes.ReplaceAll(code, []byte("[20]byte"), []byte("types.DataId"))
-
 	return code, nil
 }
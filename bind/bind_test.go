@@ -0,0 +1,352 @@
+package bind
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/airbloc/solgen/bind/language"
+	"github.com/airbloc/solgen/bind/platform"
+	"github.com/airbloc/solgen/deployment"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// exchangeDeployment has several tuples without internal types, which are numbered as they are met.
+const exchangeDeployment = `{
+  "Exchange": {
+    "address": "0x0000000000000000000000000000000000000001",
+    "abi": [
+      {"type": "function", "name": "place", "stateMutability": "nonpayable", "outputs": [],
+       "inputs": [{"name": "offer", "type": "tuple", "components": [
+         {"name": "owner", "type": "address"},
+         {"name": "price", "type": "tuple", "components": [{"name": "amount", "type": "uint256"}]}]}]},
+      {"type": "function", "name": "offers", "stateMutability": "view", "inputs": [],
+       "outputs": [{"name": "", "type": "tuple[]", "components": [
+         {"name": "owner", "type": "address"},
+         {"name": "price", "type": "tuple", "components": [{"name": "amount", "type": "uint256"}]}]}]},
+      {"type": "function", "name": "settle", "stateMutability": "nonpayable", "outputs": [],
+       "inputs": [{"name": "receipt", "type": "tuple", "components": [{"name": "id", "type": "bytes8"}, {"name": "ok", "type": "bool"}]}]},
+      {"type": "function", "name": "settle", "stateMutability": "nonpayable", "outputs": [],
+       "inputs": [{"name": "ids", "type": "bytes8[]"}]},
+      {"type": "function", "name": "cancel", "stateMutability": "nonpayable", "outputs": [],
+       "inputs": [{"name": "order", "type": "tuple", "components": [{"name": "maker", "type": "address"}, {"name": "nonce", "type": "uint64"}]}]},
+      {"type": "function", "name": "fee", "stateMutability": "view", "inputs": [],
+       "outputs": [{"name": "", "type": "tuple", "components": [{"name": "rate", "type": "uint16"}, {"name": "to", "type": "address"}]}]},
+      {"type": "event", "name": "Placed", "anonymous": false,
+       "inputs": [{"name": "owner", "type": "address", "indexed": true}, {"name": "amount", "type": "uint256", "indexed": false}]},
+      {"type": "event", "name": "Settled", "anonymous": false,
+       "inputs": [{"name": "id", "type": "bytes8", "indexed": true}]}
+    ]
+  }
+}`
+
+// registryDeployment has no tuples, whose struct types are declared outside of generated code,
+// so that its binds can be type-checked on their own.
+const registryDeployment = `{
+  "Registry": {
+    "address": "0x0000000000000000000000000000000000000002",
+    "abi": [
+      {"type": "function", "name": "register", "stateMutability": "nonpayable", "outputs": [],
+       "inputs": [{"name": "id", "type": "bytes8"}, {"name": "owner", "type": "address"}]},
+      {"type": "function", "name": "owners", "stateMutability": "view",
+       "inputs": [{"name": "ids", "type": "bytes8[]"}], "outputs": [{"name": "", "type": "address[]"}]},
+      {"type": "function", "name": "get", "stateMutability": "view",
+       "inputs": [{"name": "id", "type": "bytes8"}],
+       "outputs": [{"name": "owner", "type": "address"}, {"name": "expiry", "type": "uint256"}]},
+      {"type": "event", "name": "Registered", "anonymous": false,
+       "inputs": [{"name": "id", "type": "bytes8", "indexed": true}, {"name": "owner", "type": "address", "indexed": false}]}
+    ]
+  }
+}`
+
+func TestBindDeterministic(t *testing.T) {
+	deployments, err := deployment.GetDeploymentsFromReader(strings.NewReader(exchangeDeployment))
+	require.NoError(t, err)
+	opt := Option{Platform: platform.Ethereum, Language: language.Go}
+
+	expected, err := Bind("Exchange", deployments["Exchange"], opt)
+	require.NoError(t, err)
+	for i := 0; i < 50; i++ {
+		codes, err := Bind("Exchange", deployments["Exchange"], opt)
+		require.NoError(t, err)
+		for _, mode := range Modes {
+			if !assert.Equal(t, string(expected[mode]), string(codes[mode]), "%s of run %d", mode, i) {
+				return
+			}
+		}
+	}
+}
+
+// structDeployment has tuples with internal types, nested ones and an Offer struct of another contract,
+// and a tuple without internal type as in ABIs of solc before 0.5.11.
+const structDeployment = `{
+  "Exchange": {
+    "address": "0x0000000000000000000000000000000000000001",
+    "abi": [
+      {"type": "function", "name": "place", "stateMutability": "nonpayable", "outputs": [],
+       "inputs": [{"name": "offer", "type": "tuple", "internalType": "struct Exchange.Offer", "components": [
+         {"name": "owner", "type": "address", "internalType": "address"},
+         {"name": "price", "type": "tuple", "internalType": "struct Exchange.Price", "components": [
+           {"name": "amount", "type": "uint256", "internalType": "uint256"}]}]}]},
+      {"type": "function", "name": "offers", "stateMutability": "view", "inputs": [],
+       "outputs": [{"name": "", "type": "tuple[]", "internalType": "struct Exchange.Offer[]", "components": [
+         {"name": "owner", "type": "address", "internalType": "address"},
+         {"name": "price", "type": "tuple", "internalType": "struct Exchange.Price", "components": [
+           {"name": "amount", "type": "uint256", "internalType": "uint256"}]}]}]},
+      {"type": "function", "name": "match", "stateMutability": "nonpayable", "outputs": [],
+       "inputs": [{"name": "offer", "type": "tuple", "internalType": "struct Market.Offer", "components": [
+         {"name": "id", "type": "bytes8", "internalType": "bytes8"},
+         {"name": "ok", "type": "bool", "internalType": "bool"}]}]},
+      {"type": "function", "name": "fee", "stateMutability": "view", "inputs": [],
+       "outputs": [{"name": "", "type": "tuple", "components": [{"name": "rate", "type": "uint16"}, {"name": "to", "type": "address"}]}]}
+    ]
+  }
+}`
+
+func TestStructNames(t *testing.T) {
+	deployments, err := deployment.GetDeploymentsFromReader(strings.NewReader(structDeployment))
+	require.NoError(t, err)
+	rawABI := deployments["Exchange"].RawABI
+
+	for _, tc := range []struct {
+		name    string
+		structs map[string]string
+		names   map[string]string
+	}{
+		{
+			name: "internal types",
+			names: map[string]string{
+				"(address,(uint256))": "ExchangeOffer",
+				"(uint256)":           "Price",
+				"(bytes8,bool)":       "MarketOffer",
+			},
+		},
+		{
+			name:    "customs by internal name and tuple signature",
+			structs: map[string]string{"Exchange.Offer": "types.Offer", "(uint16,address)": "types.Fee"},
+			names: map[string]string{
+				"(address,(uint256))": "types.Offer",
+				"(uint256)":           "Price",
+				"(bytes8,bool)":       "MarketOffer",
+				"(uint16,address)":    "types.Fee",
+			},
+		},
+		{
+			name:    "customs by tuple signature of an internal type",
+			structs: map[string]string{"(uint256)": "types.Amount"},
+			names: map[string]string{
+				"(address,(uint256))": "ExchangeOffer",
+				"(uint256)":           "types.Amount",
+				"(bytes8,bool)":       "MarketOffer",
+			},
+		},
+		{
+			name:    "internal name first",
+			structs: map[string]string{"Exchange.Price": "types.Price", "(uint256)": "types.Amount"},
+			names: map[string]string{
+				"(address,(uint256))": "ExchangeOffer",
+				"(uint256)":           "types.Price",
+				"(bytes8,bool)":       "MarketOffer",
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			names, err := structNames(rawABI, Customs{Structs: tc.structs})
+			require.NoError(t, err)
+			assert.Equal(t, tc.names, names)
+		})
+	}
+}
+
+func TestBindStructs(t *testing.T) {
+	deployments, err := deployment.GetDeploymentsFromReader(strings.NewReader(structDeployment))
+	require.NoError(t, err)
+	opt := Option{Platform: platform.Ethereum, Language: language.Go, Modes: []Mode{Contract}}
+
+	codes, err := Bind("Exchange", deployments["Exchange"], opt)
+	require.NoError(t, err)
+	code := string(codes[Contract])
+	assert.Contains(t, code, "offer ExchangeOffer,")
+	assert.Contains(t, code, "offer MarketOffer,")
+	assert.Contains(t, code, "Offers(ctx context.Context) ([]ExchangeOffer, error)")
+	assert.Contains(t, code, "Fee(ctx context.Context) (Struct0, error)")
+
+	opt.Customs.Structs = map[string]string{"Exchange.Offer": "types.Offer", "(uint16,address)": "types.Fee"}
+	codes, err = Bind("Exchange", deployments["Exchange"], opt)
+	require.NoError(t, err)
+	code = string(codes[Contract])
+	assert.Contains(t, code, "offer types.Offer,")
+	assert.Contains(t, code, "Offers(ctx context.Context) ([]types.Offer, error)")
+	assert.Contains(t, code, "Fee(ctx context.Context) (types.Fee, error)")
+	assert.NotContains(t, code, "Struct0")
+}
+
+func TestBindTypeMap(t *testing.T) {
+	deployments, err := deployment.GetDeploymentsFromReader(strings.NewReader(registryDeployment))
+	require.NoError(t, err)
+	opt := Option{Platform: platform.Ethereum, Language: language.Go, Modes: []Mode{Contract}}
+
+	codes, err := Bind("Registry", deployments["Registry"], opt)
+	require.NoError(t, err)
+	assert.Contains(t, string(codes[Contract]), "ids []types.ID")
+
+	opt.Customs.TypeMap = TypeMap{
+		"bytes8[]": {Type: "[]idset.ID", Import: "example.com/idset"},
+		"bytes8":   {Type: "[8]byte"},
+	}
+	codes, err = Bind("Registry", deployments["Registry"], opt)
+	require.NoError(t, err)
+	code := string(codes[Contract])
+	assert.Contains(t, code, "ids []idset.ID")
+	assert.Contains(t, code, "id [8]byte")
+	assert.Contains(t, code, "id [][8]byte")
+	assert.Contains(t, code, `idset "example.com/idset"`)
+	assert.NotContains(t, code, "types.ID")
+	assertCompiles(t, map[string][]byte{"contracts/registry.go": codes[Contract]})
+}
+
+func TestBindTypeMapConflicts(t *testing.T) {
+	deployments, err := deployment.GetDeploymentsFromReader(strings.NewReader(registryDeployment))
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		name    string
+		typeMap TypeMap
+		err     string
+	}{
+		{
+			name:    "platform import",
+			typeMap: TypeMap{"bytes32": {Type: "types.Digest", Import: "example.com/types"}},
+			err:     "contracts: type map imports example.com/types as types, which is already imported from github.com/airbloc/airbloc-go/bind/types",
+		},
+		{
+			name: "each other",
+			typeMap: TypeMap{
+				"bytes8":  {Type: "ids.ID", Import: "example.com/ids"},
+				"bytes20": {Type: "ids.Address", Import: "example.com/legacy/ids"},
+			},
+			err: "type map of bytes20 and bytes8 import example.com/legacy/ids and example.com/ids as ids",
+		},
+		{
+			name: "same import",
+			typeMap: TypeMap{
+				"bytes8":   {Type: "idset.ID", Import: "example.com/idset"},
+				"bytes8[]": {Type: "[]idset.ID", Import: "example.com/idset"},
+				"bytes20":  {Type: "types.DataId", Import: "github.com/airbloc/airbloc-go/bind/types"},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			opt := Option{Platform: platform.Ethereum, Language: language.Go, Customs: Customs{TypeMap: tc.typeMap}}
+			_, err := Bind("Registry", deployments["Registry"], opt)
+			if tc.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.err)
+			}
+		})
+	}
+}
+
+func TestParseLanguage(t *testing.T) {
+	lang, err := ParseLanguage("Golang")
+	assert.NoError(t, err)
+	assert.Equal(t, language.Go, lang)
+
+	_, err = ParseLanguage("java")
+	assert.EqualError(t, err, `unsupported language "java" (supported: golang)`)
+}
+
+func TestBindManagersPackage(t *testing.T) {
+	deployments, err := deployment.GetDeploymentsFromReader(strings.NewReader(registryDeployment))
+	require.NoError(t, err)
+	opt := Option{
+		Platform:        platform.Ethereum,
+		Language:        language.Go,
+		Packages:        map[Mode]string{Contract: "ethcontracts", Manager: "ethmanagers"},
+		ContractsImport: buildModule + "/ethcontracts",
+	}
+
+	codes, err := Bind("Registry", deployments["Registry"], opt)
+	require.NoError(t, err)
+	assert.Contains(t, string(codes[Manager]), `ethcontracts "`+buildModule+`/ethcontracts"`)
+	assert.Contains(t, string(codes[Manager]), "ethcontracts.RegistryCaller")
+	assertCompiles(t, map[string][]byte{
+		"ethcontracts/registry.go": codes[Contract],
+		"ethmanagers/registry.go":  codes[Manager],
+	})
+}
+
+func TestBindNetworks(t *testing.T) {
+	deployments, err := deployment.GetDeploymentsFromReader(strings.NewReader(registryDeployment))
+	require.NoError(t, err)
+	registry := deployments["Registry"]
+	registry.Networks = map[string]deployment.Network{
+		"8217":  {Address: common.HexToAddress("0x0000000000000000000000000000000000008217"), CreatedAt: big.NewInt(2)},
+		"1001":  {Address: common.HexToAddress("0x0000000000000000000000000000000000001001"), CreatedAt: big.NewInt(1)},
+		"31337": {Address: common.HexToAddress("0x0000000000000000000000000000000000031337")},
+	}
+	opt := Option{Platform: platform.Ethereum, Language: language.Go, Modes: []Mode{Contract}}
+
+	codes, err := Bind("Registry", registry, opt)
+	require.NoError(t, err)
+	code := string(codes[Contract])
+	assert.Contains(t, code, "var RegistryNetworks = map[string]struct{ Address, TxHash, CreatedAt string }{\n"+
+		`	"1001":  {Address: "0x0000000000000000000000000000000000001001", TxHash: "`+common.Hash{}.Hex()+`", CreatedAt: "`+common.BigToHash(big.NewInt(1)).Hex()+`"},`+"\n"+
+		`	"8217":  {Address: "0x0000000000000000000000000000000000008217", TxHash: "`+common.Hash{}.Hex()+`", CreatedAt: "`+common.BigToHash(big.NewInt(2)).Hex()+`"},`+"\n"+
+		`	"31337": {Address: "0x0000000000000000000000000000000000031337", TxHash: "`+common.Hash{}.Hex()+`", CreatedAt: "`+common.Hash{}.Hex()+`"},`+"\n}")
+	assert.Contains(t, code, "ChainID(ctx context.Context) (*big.Int, error)")
+	assert.Contains(t, code, "network, ok := RegistryNetworks[chainID.String()]")
+	assertCompiles(t, map[string][]byte{"contracts/registry.go": codes[Contract]})
+
+	registry.Networks = map[string]deployment.Network{"klaytn": {}}
+	_, err = Bind("Registry", registry, opt)
+	assert.EqualError(t, err, `invalid chain ID "klaytn" of networks`)
+}
+
+func TestBindDeploy(t *testing.T) {
+	deployments, err := deployment.GetDeploymentsFromReader(strings.NewReader(registryDeployment))
+	require.NoError(t, err)
+	registry := deployments["Registry"]
+	registry.Bytecode = "0x6080"
+	registry.ParsedABI = append(registry.ParsedABI, map[string]interface{}{
+		"type": "constructor", "stateMutability": "nonpayable",
+		"inputs": []interface{}{
+			map[string]interface{}{"name": "owner", "type": "address"},
+			map[string]interface{}{"name": "opts", "type": "bytes8"},
+			map[string]interface{}{"name": "type", "type": "uint8"},
+			map[string]interface{}{"name": "_receipt", "type": "bool"},
+			map[string]interface{}{"name": "receipt", "type": "bool"},
+			map[string]interface{}{"name": "", "type": "uint256"},
+		},
+	})
+	raw, err := json.Marshal(registry.ParsedABI)
+	require.NoError(t, err)
+	registry.EvmABI, err = abi.JSON(bytes.NewReader(raw))
+	require.NoError(t, err)
+	opt := Option{Platform: platform.Ethereum, Language: language.Go, Modes: []Mode{Contract}}
+
+	codes, err := Bind("Registry", registry, opt)
+	require.NoError(t, err)
+	code := string(codes[Contract])
+	assert.Contains(t, code, "func DeployRegistry(\n"+
+		"\tctx context.Context,\n"+
+		"\topts *ablbind.TransactOpts,\n"+
+		"\tbackend ablbind.ContractBackend,\n"+
+		"\towner common.Address,\n"+
+		"\t_opts types.ID,\n"+
+		"\t_type uint8,\n"+
+		"\t_receipt bool,\n"+
+		"\t__receipt bool,\n"+
+		"\targ5 *big.Int,\n"+
+		") (*RegistryContract, error) {")
+	assert.Contains(t, code, "backend, owner, _opts, _type, _receipt, __receipt, arg5)")
+	assert.Contains(t, code, "ablbind.NewDeployment(address, tx.Hash(), receipt.BlockNumber, evmABI)")
+	assertCompiles(t, map[string][]byte{"contracts/registry.go": codes[Contract]})
+}
@@ -0,0 +1,202 @@
+package bind
+
+import (
+	"go/ast"
+	"go/build"
+	"go/format"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// buildModule is the module generated binds are type-checked in by assertCompiles.
+const buildModule = "example.com/build"
+
+// stdImports are the standard packages generated code may use without importing them,
+// as the output is expected to go through goimports.
+var stdImports = []string{"context", "errors", "fmt"}
+
+// fixImports does the part of goimports generated code relies on:
+// it removes unused imports and adds missing standard ones.
+func fixImports(t *testing.T, fset *token.FileSet, filename string, code []byte) *ast.File {
+	file, err := parser.ParseFile(token.NewFileSet(), filename, code, parser.ParseComments)
+	require.NoError(t, err, "parse %s", filename)
+
+	used := make(map[string]bool)
+	ast.Inspect(file, func(node ast.Node) bool {
+		if sel, ok := node.(*ast.SelectorExpr); ok {
+			if ident, ok := sel.X.(*ast.Ident); ok && ident.Obj == nil {
+				used[ident.Name] = true
+			}
+		}
+		return true
+	})
+
+	imported := make(map[string]bool)
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.IMPORT {
+			continue
+		}
+		var specs []ast.Spec
+		for _, spec := range gen.Specs {
+			imp := spec.(*ast.ImportSpec)
+			importPath, _ := strconv.Unquote(imp.Path.Value)
+			name := path.Base(importPath)
+			if imp.Name != nil {
+				name = imp.Name.Name
+			}
+			if used[name] {
+				specs = append(specs, spec)
+				imported[name] = true
+			}
+		}
+		for _, std := range stdImports {
+			if used[std] && !imported[std] {
+				specs = append(specs, &ast.ImportSpec{Path: &ast.BasicLit{Kind: token.STRING, Value: strconv.Quote(std)}})
+				imported[std] = true
+			}
+		}
+		gen.Specs = specs
+	}
+
+	// Print and parse the file again, so that positions of added imports are valid
+	var fixed strings.Builder
+	require.NoError(t, format.Node(&fixed, token.NewFileSet(), file))
+	file, err = parser.ParseFile(fset, filename, fixed.String(), 0)
+	require.NoError(t, err, "parse fixed %s", filename)
+	return file
+}
+
+// sourceImporter type-checks dependencies from source: go-ethereum and the packages it vendors
+// from the module cache, and stubs of the airbloc packages and of mapped types from testdata. Errors in dependencies
+// are ignored, as only the declarations generated code refers to matter.
+type sourceImporter struct {
+	fset     *token.FileSet
+	std      types.Importer
+	roots    map[string]string // Source directories by import path prefix, "" for vendored packages
+	packages map[string]*types.Package
+}
+
+func newSourceImporter(t *testing.T, fset *token.FileSet) *sourceImporter {
+	out, err := exec.Command("go", "list", "-m", "-f", "{{.Dir}}", "github.com/ethereum/go-ethereum").Output()
+	require.NoError(t, err)
+	ethereum := strings.TrimSpace(string(out))
+	testdata, err := filepath.Abs("testdata")
+	require.NoError(t, err)
+
+	return &sourceImporter{
+		fset: fset,
+		std:  importer.Default(),
+		roots: map[string]string{
+			"github.com/ethereum/go-ethereum": ethereum,
+			"github.com/airbloc/airbloc-go":   filepath.Join(testdata, "airbloc-go"),
+			"github.com/airbloc/logger":       filepath.Join(testdata, "logger"),
+			"example.com/idset":               filepath.Join(testdata, "idset"),
+			"":                                filepath.Join(ethereum, "vendor"),
+		},
+		packages: make(map[string]*types.Package),
+	}
+}
+
+// dir returns the source directory of a package, or false for standard packages.
+func (imp *sourceImporter) dir(importPath string) (string, bool) {
+	prefixes := make([]string, 0, len(imp.roots))
+	for prefix := range imp.roots {
+		prefixes = append(prefixes, prefix)
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(prefixes)))
+
+	for _, prefix := range prefixes {
+		rel := importPath
+		if prefix != "" {
+			if importPath != prefix && !strings.HasPrefix(importPath, prefix+"/") {
+				continue
+			}
+			rel = strings.TrimPrefix(importPath, prefix)
+		}
+		dir := filepath.Join(imp.roots[prefix], filepath.FromSlash(rel))
+		if pkg, err := build.ImportDir(dir, 0); err == nil && len(pkg.GoFiles) > 0 {
+			return dir, true
+		}
+	}
+	return "", false
+}
+
+func (imp *sourceImporter) Import(importPath string) (*types.Package, error) {
+	if pkg, ok := imp.packages[importPath]; ok {
+		return pkg, nil
+	}
+	dir, ok := imp.dir(importPath)
+	if !ok {
+		return imp.std.Import(importPath)
+	}
+
+	info, err := build.ImportDir(dir, 0)
+	if err != nil {
+		return nil, err
+	}
+	var files []*ast.File
+	for _, name := range append(info.GoFiles, info.CgoFiles...) {
+		if file, err := parser.ParseFile(imp.fset, filepath.Join(dir, name), nil, 0); err == nil {
+			files = append(files, file)
+		}
+	}
+	conf := types.Config{Importer: imp, FakeImportC: true, IgnoreFuncBodies: true, Error: func(error) {}}
+	pkg, _ := conf.Check(importPath, imp.fset, files, nil)
+	imp.packages[importPath] = pkg
+	return pkg, nil
+}
+
+// assertCompiles type-checks generated files, keyed by their path relative to buildModule,
+// against go-ethereum and stubs of the airbloc packages found in testdata.
+func assertCompiles(t *testing.T, files map[string][]byte) {
+	if testing.Short() {
+		t.Skip("type-checking generated code is skipped in short mode")
+	}
+	fset := token.NewFileSet()
+	imp := newSourceImporter(t, fset)
+
+	pkgFiles := make(map[string][]*ast.File)
+	for name, code := range files {
+		pkgPath := path.Join(buildModule, path.Dir(name))
+		pkgFiles[pkgPath] = append(pkgFiles[pkgPath], fixImports(t, fset, name, code))
+	}
+
+	// Generated packages import each other, so check them after the ones they import
+	checked := make(map[string]bool)
+	var check func(pkgPath string)
+	check = func(pkgPath string) {
+		if checked[pkgPath] {
+			return
+		}
+		checked[pkgPath] = true
+		for _, file := range pkgFiles[pkgPath] {
+			for _, spec := range file.Imports {
+				dep, _ := strconv.Unquote(spec.Path.Value)
+				if _, ok := pkgFiles[dep]; ok {
+					check(dep)
+				}
+			}
+		}
+
+		var errs []string
+		conf := types.Config{Importer: imp, Error: func(err error) { errs = append(errs, err.Error()) }}
+		pkg, _ := conf.Check(pkgPath, fset, pkgFiles[pkgPath], nil)
+		require.Empty(t, errs, "type-check %s", pkgPath)
+		imp.packages[pkgPath] = pkg
+	}
+	for pkgPath := range pkgFiles {
+		check(pkgPath)
+	}
+}
@@ -1,7 +1,230 @@
 package bind
 
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/airbloc/solgen/bind/language"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+)
+
 type Customs struct {
-	Structs map[string]string `json:"structs"`
-	Imports map[string]string `json:"imports"`
-	Methods map[string]bool   `json:"methods"`
+	Structs map[string]string `json:"structs" yaml:"structs"`
+	Imports map[string]string `json:"imports" yaml:"imports"`
+	Methods map[string]bool   `json:"methods" yaml:"methods"`   // Methods bound (true) or not (false) by name
+	Allow   []string          `json:"allow" yaml:"allow"`       // Glob patterns of methods bound, every method if empty and Methods has no true entry
+	Deny    []string          `json:"deny" yaml:"deny"`         // Glob patterns of methods not bound
+	Events  Filter            `json:"events" yaml:"events"`     // Events bound, every event by default
+	Aliases map[string]string `json:"aliases" yaml:"aliases"`   // Names of methods and events by signature, such as transfer(address,uint256,bytes)
+	TypeMap TypeMap           `json:"type_map" yaml:"type_map"` // Types of the target language by Solidity type, such as bytes8
+}
+
+// TypeMap binds Solidity types, such as bytes8 or bytes20[], to types of the target language.
+// Its entries take precedence over the default type map of the language.
+type TypeMap map[string]TypeMapping
+
+// TypeMapping is a type of the target language imported from Import, if given.
+// It may be written as the type alone, such as "types.ID".
+type TypeMapping struct {
+	Type   string `json:"type" yaml:"type"`
+	Import string `json:"import,omitempty" yaml:"import,omitempty"`
+}
+
+func (m *TypeMapping) UnmarshalJSON(data []byte) error {
+	var typ string
+	if err := json.Unmarshal(data, &typ); err == nil {
+		*m = TypeMapping{Type: typ}
+		return nil
+	}
+	type plain TypeMapping
+	return json.Unmarshal(data, (*plain)(m))
+}
+
+func (m *TypeMapping) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var typ string
+	if err := unmarshal(&typ); err == nil {
+		*m = TypeMapping{Type: typ}
+		return nil
+	}
+	type plain TypeMapping
+	return unmarshal((*plain)(m))
+}
+
+// Merge returns the type map overridden by the entries of other.
+func (t TypeMap) Merge(other TypeMap) TypeMap {
+	merged := make(TypeMap, len(t)+len(other))
+	for sol, mapping := range t {
+		merged[sol] = mapping
+	}
+	for sol, mapping := range other {
+		merged[sol] = mapping
+	}
+	return merged
+}
+
+// bind resolves the type map of the language with the imports of the mapped types, keyed by package name.
+// The package name is the qualifier of the type, or else the last element of the import path.
+// Types importing different paths under the same package name are an error.
+func (t TypeMap) bind(lang language.Language) (language.TypeMap, map[string]string, error) {
+	types := make(language.TypeMap)
+	for sol, typ := range language.DefaultTypeMap[lang] {
+		types[sol] = typ
+	}
+
+	sols := make([]string, 0, len(t))
+	for sol := range t {
+		sols = append(sols, sol)
+	}
+	sort.Strings(sols)
+
+	imports := make(map[string]string)
+	importers := make(map[string]string)
+	for _, sol := range sols {
+		mapping := t[sol]
+		types[sol] = mapping.Type
+		if mapping.Import == "" {
+			continue
+		}
+		name := path.Base(mapping.Import)
+		if qualified := strings.TrimLeft(mapping.Type, "[]*0123456789"); strings.Contains(qualified, ".") {
+			name = qualified[:strings.Index(qualified, ".")]
+		}
+		if other, ok := imports[name]; ok && other != mapping.Import {
+			return nil, nil, fmt.Errorf("type map of %s and %s import %s and %s as %s", importers[name], sol, other, mapping.Import, name)
+		}
+		imports[name] = mapping.Import
+		importers[name] = sol
+	}
+	return types, imports, nil
+}
+
+// Filter selects methods or events by glob patterns, as in path.Match, of their names or signatures.
+// Every member is selected if there is no Allow pattern.
+type Filter struct {
+	Allow []string `json:"allow" yaml:"allow"`
+	Deny  []string `json:"deny" yaml:"deny"`
+}
+
+// matches reports whether the pattern matches any of the names of a member.
+func matches(pattern string, names ...string) bool {
+	for _, name := range names {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (f Filter) match(names ...string) bool {
+	for _, pattern := range f.Deny {
+		if matches(pattern, names...) {
+			return false
+		}
+	}
+	if len(f.Allow) == 0 {
+		return true
+	}
+	for _, pattern := range f.Allow {
+		if matches(pattern, names...) {
+			return true
+		}
+	}
+	return false
+}
+
+// methodFilter merges the Methods entries into the Allow and Deny patterns.
+// Names are literal patterns, as identifiers hold no glob metacharacters.
+func (c Customs) methodFilter() Filter {
+	f := Filter{
+		Allow: append([]string{}, c.Allow...),
+		Deny:  append([]string{}, c.Deny...),
+	}
+	for name, bound := range c.Methods {
+		if bound {
+			f.Allow = append(f.Allow, name)
+		} else {
+			f.Deny = append(f.Deny, name)
+		}
+	}
+	return f
+}
+
+// BindsMethod reports whether the method is bound. Methods are matched by their name in the ABI
+// (suffixed by an index if overloaded), their original name or their signature.
+func (c Customs) BindsMethod(name string, method abi.Method) bool {
+	return c.methodFilter().match(name, method.RawName, method.Sig())
+}
+
+// BindsEvent reports whether the event is bound. Events are matched by their name in the ABI
+// (suffixed by an index if overloaded), their original name or their signature.
+func (c Customs) BindsEvent(name string, event abi.Event) bool {
+	return c.Events.match(name, event.RawName, event.Sig())
+}
+
+// Unknown describes every method name and pattern of the customs matching no method of the ABI,
+// and every event pattern matching no event. They are most likely typos.
+func (c Customs) Unknown(evmABI abi.ABI) []string {
+	var unknown []string
+
+	methodNames := make([][]string, 0, len(evmABI.Methods))
+	for name, method := range evmABI.Methods {
+		methodNames = append(methodNames, []string{name, method.RawName, method.Sig()})
+	}
+	for name := range c.Methods {
+		if !matchesAny(name, methodNames) {
+			unknown = append(unknown, fmt.Sprintf("method %q", name))
+		}
+	}
+	for _, pattern := range append(append([]string{}, c.Allow...), c.Deny...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			unknown = append(unknown, fmt.Sprintf("method pattern %q (%v)", pattern, err))
+		} else if !matchesAny(pattern, methodNames) {
+			unknown = append(unknown, fmt.Sprintf("method pattern %q", pattern))
+		}
+	}
+
+	eventNames := make([][]string, 0, len(evmABI.Events))
+	for name, event := range evmABI.Events {
+		eventNames = append(eventNames, []string{name, event.RawName, event.Sig()})
+	}
+	for _, pattern := range append(append([]string{}, c.Events.Allow...), c.Events.Deny...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			unknown = append(unknown, fmt.Sprintf("event pattern %q (%v)", pattern, err))
+		} else if !matchesAny(pattern, eventNames) {
+			unknown = append(unknown, fmt.Sprintf("event pattern %q", pattern))
+		}
+	}
+
+	for sig := range c.Aliases {
+		if !hasSig(normalizeSig(sig), methodMembers(evmABI), eventMembers(evmABI)) {
+			unknown = append(unknown, fmt.Sprintf("alias signature %q", sig))
+		}
+	}
+
+	sort.Strings(unknown)
+	return unknown
+}
+
+func hasSig(sig string, memberLists ...[]member) bool {
+	for _, members := range memberLists {
+		for _, m := range members {
+			if m.sig == sig {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func matchesAny(pattern string, members [][]string) bool {
+	for _, names := range members {
+		if matches(pattern, names...) {
+			return true
+		}
+	}
+	return false
 }
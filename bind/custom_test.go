@@ -0,0 +1,129 @@
+package bind
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/airbloc/solgen/deployment"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func exchangeABI(t *testing.T) abi.ABI {
+	deployments, err := deployment.GetDeploymentsFromReader(strings.NewReader(exchangeDeployment))
+	require.NoError(t, err)
+	return deployments["Exchange"].EvmABI
+}
+
+func TestFilter(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		filter   Filter
+		selected []string
+		skipped  []string
+	}{
+		{name: "everything", selected: []string{"Placed", "Settled"}},
+		{name: "allow", filter: Filter{Allow: []string{"Place*"}}, selected: []string{"Placed"}, skipped: []string{"Settled"}},
+		{name: "deny", filter: Filter{Deny: []string{"Place?"}}, selected: []string{"Settled"}, skipped: []string{"Placed"}},
+		{
+			name:     "deny wins over allow",
+			filter:   Filter{Allow: []string{"*ed"}, Deny: []string{"Settled"}},
+			selected: []string{"Placed"},
+			skipped:  []string{"Settled", "Cancel"},
+		},
+		{name: "invalid pattern matches nothing", filter: Filter{Allow: []string{"[Placed"}}, skipped: []string{"Placed"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			for _, name := range tc.selected {
+				assert.True(t, tc.filter.match(name), name)
+			}
+			for _, name := range tc.skipped {
+				assert.False(t, tc.filter.match(name), name)
+			}
+		})
+	}
+}
+
+func TestBindsMethod(t *testing.T) {
+	evmABI := exchangeABI(t)
+	signatures := make(map[string]string)
+	for key, method := range evmABI.Methods {
+		signatures[method.Sig()] = key
+	}
+	all := []string{"cancel((address,uint64))", "fee()", "offers()", "place((address,(uint256)))", "settle((bytes8,bool))", "settle(bytes8[])"}
+
+	for _, tc := range []struct {
+		name    string
+		customs Customs
+		bound   []string
+	}{
+		{name: "every method", bound: all},
+		{name: "allow", customs: Customs{Allow: []string{"settle"}}, bound: []string{"settle((bytes8,bool))", "settle(bytes8[])"}},
+		{name: "deny", customs: Customs{Deny: []string{"*e"}}, bound: []string{"cancel((address,uint64))", "offers()"}},
+		{
+			name:    "deny wins over allow",
+			customs: Customs{Allow: []string{"settle*", "fee"}, Deny: []string{"fee"}},
+			bound:   []string{"settle((bytes8,bool))", "settle(bytes8[])"},
+		},
+		{
+			name:    "signature glob",
+			customs: Customs{Deny: []string{`settle(bytes8\[\])`, "place(*)"}},
+			bound:   []string{"cancel((address,uint64))", "fee()", "offers()", "settle((bytes8,bool))"},
+		},
+		{
+			name:    "signature glob of inputs",
+			customs: Customs{Allow: []string{"*(bytes8*"}},
+			bound:   []string{"settle((bytes8,bool))", "settle(bytes8[])"},
+		},
+		{name: "legacy methods bound", customs: Customs{Methods: map[string]bool{"cancel": true, "fee": true}}, bound: []string{"cancel((address,uint64))", "fee()"}},
+		{
+			name:    "legacy methods not bound",
+			customs: Customs{Methods: map[string]bool{"cancel": false, "offers": false}},
+			bound:   []string{"fee()", "place((address,(uint256)))", "settle((bytes8,bool))", "settle(bytes8[])"},
+		},
+		{
+			name:    "legacy methods with patterns",
+			customs: Customs{Methods: map[string]bool{"fee": true, "settle": false}, Allow: []string{"settle*", "offers"}},
+			bound:   []string{"fee()", "offers()"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var bound []string
+			for _, sig := range all {
+				key := signatures[sig]
+				if tc.customs.BindsMethod(key, evmABI.Methods[key]) {
+					bound = append(bound, sig)
+				}
+			}
+			assert.Equal(t, tc.bound, bound)
+		})
+	}
+}
+
+func TestBindsEvent(t *testing.T) {
+	evmABI := exchangeABI(t)
+	for _, tc := range []struct {
+		name    string
+		customs Customs
+		bound   []string
+	}{
+		{name: "every event", bound: []string{"Placed", "Settled"}},
+		{name: "allow", customs: Customs{Events: Filter{Allow: []string{"Place?"}}}, bound: []string{"Placed"}},
+		{name: "deny", customs: Customs{Events: Filter{Deny: []string{"Placed"}}}, bound: []string{"Settled"}},
+		{name: "signature glob", customs: Customs{Events: Filter{Deny: []string{"*(bytes8)"}}}, bound: []string{"Placed"}},
+		{name: "deny wins over allow", customs: Customs{Events: Filter{Allow: []string{"*"}, Deny: []string{"*ed(*)"}}}},
+		{name: "method patterns don't apply", customs: Customs{Deny: []string{"*"}, Methods: map[string]bool{"Placed": false}}, bound: []string{"Placed", "Settled"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var bound []string
+			for _, name := range []string{"Placed", "Settled"} {
+				if tc.customs.BindsEvent(name, evmABI.Events[name]) {
+					bound = append(bound, name)
+				}
+			}
+			assert.Equal(t, tc.bound, bound)
+		})
+	}
+}
@@ -11,15 +11,18 @@ import (
 
 // bindTypeGo converts solidity types to Go ones. Since there is no clear mapping
 // from all Solidity types to Go ones (e.g. uint17), those that cannot be exactly
-// mapped will use an upscaled type (e.g. BigDecimal).
-func bindTypeGo(kind abi.Type, structs map[string]*template.Struct) string {
+// mapped will use an upscaled type (e.g. BigDecimal). Types found in the type map are bound as mapped.
+func bindTypeGo(kind abi.Type, structs map[string]*template.Struct, types TypeMap) string {
+	if mapped, ok := types[kind.String()]; ok {
+		return mapped
+	}
 	switch kind.T {
 	case abi.TupleTy:
 		return structs[kind.String()].Name
 	case abi.ArrayTy:
-		return fmt.Sprintf("[%d]", kind.Size) + bindTypeGo(*kind.Elem, structs)
+		return fmt.Sprintf("[%d]", kind.Size) + bindTypeGo(*kind.Elem, structs, types)
 	case abi.SliceTy:
-		return "[]" + bindTypeGo(*kind.Elem, structs)
+		return "[]" + bindTypeGo(*kind.Elem, structs, types)
 	default:
 		return bindBasicTypeGo(kind)
 	}
@@ -51,8 +54,8 @@ func bindBasicTypeGo(kind abi.Type) string {
 
 // bindTopicTypeGo converts a Solidity topic type to a Go one. It is almost the same
 // funcionality as for simple types, but dynamic types get converted to hashes.
-func bindTopicTypeGo(kind abi.Type, structs map[string]*template.Struct) string {
-	bound := bindTypeGo(kind, structs)
+func bindTopicTypeGo(kind abi.Type, structs map[string]*template.Struct, types TypeMap) string {
+	bound := bindTypeGo(kind, structs, types)
 	if bound == "string" || bound == "[]byte" {
 		bound = "common.Hash"
 	}
@@ -60,9 +63,9 @@ func bindTopicTypeGo(kind abi.Type, structs map[string]*template.Struct) string
 }
 
 // bindStructTypeGo converts a Solidity tuple type to a Go one and records the mapping
-// in the given map.
+// in the given map. Structs are named by names, keyed by tuple signature, or numbered otherwise.
 // Notably, this function will resolve and record nested struct recursively.
-func bindStructTypeGo(kind abi.Type, structs map[string]*template.Struct) string {
+func bindStructTypeGo(kind abi.Type, structs map[string]*template.Struct, names map[string]string) string {
 	switch kind.T {
 	case abi.TupleTy:
 		if s, exist := structs[kind.String()]; exist {
@@ -70,19 +73,22 @@ func bindStructTypeGo(kind abi.Type, structs map[string]*template.Struct) string
 		}
 		var fields []*template.Field
 		for i, elem := range kind.TupleElems {
-			field := bindStructTypeGo(*elem, structs)
+			field := bindStructTypeGo(*elem, structs, names)
 			fields = append(fields, &template.Field{Type: field, Name: utils.Capitalise(kind.TupleRawNames[i]), SolKind: *elem})
 		}
-		name := fmt.Sprintf("Struct%d", len(structs))
+		name, ok := names[kind.String()]
+		if !ok {
+			name = fmt.Sprintf("Struct%d", len(structs))
+		}
 		structs[kind.String()] = &template.Struct{
 			Name:   name,
 			Fields: fields,
 		}
 		return name
 	case abi.ArrayTy:
-		return fmt.Sprintf("[%d]", kind.Size) + bindStructTypeGo(*kind.Elem, structs)
+		return fmt.Sprintf("[%d]", kind.Size) + bindStructTypeGo(*kind.Elem, structs, names)
 	case abi.SliceTy:
-		return "[]" + bindStructTypeGo(*kind.Elem, structs)
+		return "[]" + bindStructTypeGo(*kind.Elem, structs, names)
 	default:
 		return bindBasicTypeGo(kind)
 	}
@@ -72,13 +72,16 @@ func pluralizeJavaType(typ string) string {
 
 // bindTypeJava converts a Solidity type to a Java one. Since there is no clear mapping
 // from all Solidity types to Java ones (e.g. uint17), those that cannot be exactly
-// mapped will use an upscaled type (e.g. BigDecimal).
-func bindTypeJava(kind abi.Type, structs map[string]*template.Struct) string {
+// mapped will use an upscaled type (e.g. BigDecimal). Types found in the type map are bound as mapped.
+func bindTypeJava(kind abi.Type, structs map[string]*template.Struct, types TypeMap) string {
+	if mapped, ok := types[kind.String()]; ok {
+		return mapped
+	}
 	switch kind.T {
 	case abi.TupleTy:
 		return structs[kind.String()].Name
 	case abi.ArrayTy, abi.SliceTy:
-		return pluralizeJavaType(bindTypeJava(*kind.Elem, structs))
+		return pluralizeJavaType(bindTypeJava(*kind.Elem, structs, types))
 	default:
 		return bindBasicTypeJava(kind)
 	}
@@ -86,8 +89,8 @@ func bindTypeJava(kind abi.Type, structs map[string]*template.Struct) string {
 
 // bindTopicTypeJava converts a Solidity topic type to a Java one. It is almost the same
 // funcionality as for simple types, but dynamic types get converted to hashes.
-func bindTopicTypeJava(kind abi.Type, structs map[string]*template.Struct) string {
-	bound := bindTypeJava(kind, structs)
+func bindTopicTypeJava(kind abi.Type, structs map[string]*template.Struct, types TypeMap) string {
+	bound := bindTypeJava(kind, structs, types)
 	if bound == "String" || bound == "byte[]" {
 		bound = "Hash"
 	}
@@ -95,9 +98,9 @@ func bindTopicTypeJava(kind abi.Type, structs map[string]*template.Struct) strin
 }
 
 // bindStructTypeJava converts a Solidity tuple type to a Java one and records the mapping
-// in the given map.
+// in the given map. Classes are named by names, keyed by tuple signature, or numbered otherwise.
 // Notably, this function will resolve and record nested struct recursively.
-func bindStructTypeJava(kind abi.Type, structs map[string]*template.Struct) string {
+func bindStructTypeJava(kind abi.Type, structs map[string]*template.Struct, names map[string]string) string {
 	switch kind.T {
 	case abi.TupleTy:
 		if s, exist := structs[kind.String()]; exist {
@@ -105,17 +108,20 @@ func bindStructTypeJava(kind abi.Type, structs map[string]*template.Struct) stri
 		}
 		var fields []*template.Field
 		for i, elem := range kind.TupleElems {
-			field := bindStructTypeJava(*elem, structs)
+			field := bindStructTypeJava(*elem, structs, names)
 			fields = append(fields, &template.Field{Type: field, Name: utils.Decapitalise(kind.TupleRawNames[i]), SolKind: *elem})
 		}
-		name := fmt.Sprintf("Class%d", len(structs))
+		name, ok := names[kind.String()]
+		if !ok {
+			name = fmt.Sprintf("Class%d", len(structs))
+		}
 		structs[kind.String()] = &template.Struct{
 			Name:   name,
 			Fields: fields,
 		}
 		return name
 	case abi.ArrayTy, abi.SliceTy:
-		return pluralizeJavaType(bindStructTypeJava(*kind.Elem, structs))
+		return pluralizeJavaType(bindStructTypeJava(*kind.Elem, structs, names))
 	default:
 		return bindBasicTypeJava(kind)
 	}
@@ -1,6 +1,8 @@
 package language
 
 import (
+	"sort"
+
 	"github.com/airbloc/solgen/bind/template"
 	"github.com/airbloc/solgen/utils"
 	"github.com/ethereum/go-ethereum/accounts/abi"
@@ -13,21 +15,44 @@ const (
 	Java Language = "java"
 )
 
-var BindType = map[Language]func(kind abi.Type, structs map[string]*template.Struct) string{
+// Languages returns every language registered in BindType, sorted by name.
+func Languages() []Language {
+	langs := make([]Language, 0, len(BindType))
+	for lang := range BindType {
+		langs = append(langs, lang)
+	}
+	sort.Slice(langs, func(i, j int) bool { return langs[i] < langs[j] })
+	return langs
+}
+
+// TypeMap maps Solidity types, such as bytes8 or bytes20[], to types of the target language.
+// Types of arrays and slices not found are bound from the mapping of their elements.
+type TypeMap map[string]string
+
+// DefaultTypeMap is the type map of each language, before the ones of customs.
+var DefaultTypeMap = map[Language]TypeMap{
+	Go: {
+		"bytes8":  "types.ID",
+		"bytes20": "types.DataId",
+		"bytes32": "common.Hash",
+	},
+}
+
+var BindType = map[Language]func(kind abi.Type, structs map[string]*template.Struct, types TypeMap) string{
 	Go:   bindTypeGo,
 	Java: bindTypeJava,
 }
 
 // bindTopicType is a set of type binders that convert Solidity types to some
 // supported programming language topic types.
-var BindTopicType = map[Language]func(kind abi.Type, structs map[string]*template.Struct) string{
+var BindTopicType = map[Language]func(kind abi.Type, structs map[string]*template.Struct, types TypeMap) string{
 	Go:   bindTopicTypeGo,
 	Java: bindTopicTypeJava,
 }
 
 // bindStructType is a set of type binders that convert Solidity tuple types to some supported
-// programming language struct definition.
-var BindStructType = map[Language]func(kind abi.Type, structs map[string]*template.Struct) string{
+// programming language struct definition, named by the given names keyed by tuple signature.
+var BindStructType = map[Language]func(kind abi.Type, structs map[string]*template.Struct, names map[string]string) string{
 	Go:   bindStructTypeGo,
 	Java: bindStructTypeJava,
 }
@@ -39,6 +64,12 @@ var NamedType = map[Language]func(string, abi.Type) string{
 	Java: namedTypeJava,
 }
 
+// FileExtension is the extension of the source files generated for each language.
+var FileExtension = map[Language]string{
+	Go:   ".go",
+	Java: ".java",
+}
+
 // methodNormalizer is a name transformer that modifies Solidity method names to
 // conform to target language naming concentions.
 var MethodNormalizer = map[Language]func(string) string{
@@ -0,0 +1,241 @@
+package bind
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/airbloc/solgen/bind/language"
+	"github.com/airbloc/solgen/utils"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+)
+
+// member is a method or event of a contract, keyed by its name in the ABI.
+type member struct {
+	key     string // Name in the ABI, suffixed by an index in declaration order if overloaded
+	rawName string
+	sig     string
+	inputs  abi.Arguments
+}
+
+func methodMembers(evmABI abi.ABI) []member {
+	members := make([]member, 0, len(evmABI.Methods))
+	for key, method := range evmABI.Methods {
+		members = append(members, member{key: key, rawName: method.RawName, sig: method.Sig(), inputs: method.Inputs})
+	}
+	return members
+}
+
+func eventMembers(evmABI abi.ABI) []member {
+	members := make([]member, 0, len(evmABI.Events))
+	for key, event := range evmABI.Events {
+		members = append(members, member{key: key, rawName: event.RawName, sig: event.Sig(), inputs: event.Inputs})
+	}
+	return members
+}
+
+// typeSuffix spells an ABI type as a part of an identifier, such as Uint256Array for uint256[].
+func typeSuffix(typ abi.Type) string {
+	switch typ.T {
+	case abi.SliceTy:
+		return typeSuffix(*typ.Elem) + "Array"
+	case abi.ArrayTy:
+		return typeSuffix(*typ.Elem) + "Array" + strconv.Itoa(typ.Size)
+	case abi.TupleTy:
+		return "Tuple"
+	default:
+		return utils.Capitalise(typ.String())
+	}
+}
+
+// normalizeSig strips the spaces of a signature, so that aliases may be written loosely.
+func normalizeSig(sig string) string {
+	return strings.Join(strings.Fields(sig), "")
+}
+
+// memberNames names the members in the target language, keyed by their ABI names.
+// Overloaded members are named after their input types instead of their declaration order,
+// such as TransferAddressUint256, unless their signature is aliased in the customs.
+// Aliases are normalized like the names of the ABI, so that transferAndCall is bound as TransferAndCall in Go.
+func memberNames(kind string, members []member, aliases map[string]string, lang language.Language) (map[string]string, error) {
+	overloads := make(map[string]int)
+	for _, m := range members {
+		overloads[m.rawName]++
+	}
+	normalizedAliases := make(map[string]string, len(aliases))
+	for sig, alias := range aliases {
+		normalizedAliases[normalizeSig(sig)] = alias
+	}
+
+	names := make(map[string]string, len(members))
+	owners := make(map[string][]string)
+	for _, m := range members {
+		name, aliased := normalizedAliases[m.sig]
+		if !aliased {
+			name = m.rawName
+			if overloads[m.rawName] > 1 {
+				for _, input := range m.inputs {
+					name += typeSuffix(input.Type)
+				}
+			}
+		}
+		name = language.MethodNormalizer[lang](name)
+		names[m.key] = name
+		owners[name] = append(owners[name], m.sig)
+	}
+
+	var collisions []string
+	for name, sigs := range owners {
+		if len(sigs) > 1 {
+			sort.Strings(sigs)
+			collisions = append(collisions, fmt.Sprintf("%s (%s)", name, strings.Join(sigs, ", ")))
+		}
+	}
+	if len(collisions) > 0 {
+		sort.Strings(collisions)
+		return nil, fmt.Errorf("several %ss are named %s, alias their signatures in customs", kind, strings.Join(collisions, ", "))
+	}
+	return names, nil
+}
+
+// MethodNames names the methods bound by the customs in the language, keyed by their names in the ABI.
+// Methods are overloaded only if several of them are bound.
+func MethodNames(evmABI abi.ABI, customs Customs, lang language.Language) (map[string]string, error) {
+	var bound []member
+	for _, m := range methodMembers(evmABI) {
+		if customs.BindsMethod(m.key, evmABI.Methods[m.key]) {
+			bound = append(bound, m)
+		}
+	}
+	return memberNames("method", bound, customs.Aliases, lang)
+}
+
+// EventNames names the events bound by the customs in the language, keyed by their names in the ABI.
+// Anonymous events are not bound, as they can't be filtered.
+func EventNames(evmABI abi.ABI, customs Customs, lang language.Language) (map[string]string, error) {
+	var bound []member
+	for _, m := range eventMembers(evmABI) {
+		if event := evmABI.Events[m.key]; !event.Anonymous && customs.BindsEvent(m.key, event) {
+			bound = append(bound, m)
+		}
+	}
+	return memberNames("event", bound, customs.Aliases, lang)
+}
+
+// abiParam is an input, output or tuple component of a JSON ABI entry, with its internal type
+// such as "struct Exchange.Offer[]" given by solc 0.5.11 and later.
+type abiParam struct {
+	Name         string     `json:"name"`
+	Type         string     `json:"type"`
+	InternalType string     `json:"internalType"`
+	Components   []abiParam `json:"components"`
+}
+
+func (p abiParam) marshaling() abi.ArgumentMarshaling {
+	components := make([]abi.ArgumentMarshaling, len(p.Components))
+	for i, c := range p.Components {
+		components[i] = c.marshaling()
+	}
+	return abi.ArgumentMarshaling{Name: p.Name, Type: p.Type, Components: components}
+}
+
+var arraySuffixPattern = regexp.MustCompile(`(\[[0-9]*\])+$`)
+
+// collectStructs records the internal struct names of the tuples of the parameter and its components,
+// keyed by tuple signature. Tuples without an internal type are recorded without a name.
+func collectStructs(param abiParam, internalNames map[string][]string) error {
+	if !strings.HasPrefix(param.Type, "tuple") {
+		return nil
+	}
+	typ, err := abi.NewType(param.Type, param.marshaling().Components)
+	if err != nil {
+		return err
+	}
+	for typ.T != abi.TupleTy {
+		typ = *typ.Elem
+	}
+	sig := typ.String()
+	if _, ok := internalNames[sig]; !ok {
+		internalNames[sig] = nil
+	}
+	if name := strings.TrimPrefix(param.InternalType, "struct "); name != param.InternalType {
+		internalNames[sig] = append(internalNames[sig], arraySuffixPattern.ReplaceAllString(name, ""))
+	}
+	for _, c := range param.Components {
+		if err := collectStructs(c, internalNames); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// structNames names the structs of the ABI, keyed by tuple signature. A struct is named by the customs
+// entry of its internal name (Exchange.Offer) or else of its signature, or else after its internal name:
+// Offer, or ExchangeOffer if several structs are called Offer. Tuples without any of them are left unnamed.
+func structNames(rawABI []byte, customs Customs) (map[string]string, error) {
+	var entries []struct {
+		Inputs  []abiParam `json:"inputs"`
+		Outputs []abiParam `json:"outputs"`
+	}
+	if err := json.Unmarshal(rawABI, &entries); err != nil {
+		return nil, err
+	}
+	internalNames := make(map[string][]string)
+	for _, entry := range entries {
+		for _, param := range append(entry.Inputs, entry.Outputs...) {
+			if err := collectStructs(param, internalNames); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// Structs of the same layout share a type, named after the first of their names
+	qualified := make(map[string]string)
+	sigsOf := make(map[string]map[string]bool)
+	for sig, names := range internalNames {
+		if len(names) == 0 {
+			continue
+		}
+		sort.Strings(names)
+		qualified[sig] = names[0]
+		short := names[0][strings.LastIndex(names[0], ".")+1:]
+		if sigsOf[short] == nil {
+			sigsOf[short] = make(map[string]bool)
+		}
+		sigsOf[short][sig] = true
+	}
+
+	names := make(map[string]string, len(internalNames))
+	derived := make(map[string][]string)
+	for sig := range internalNames {
+		if name, ok := customs.Structs[qualified[sig]]; ok && qualified[sig] != "" {
+			names[sig] = name
+		} else if name, ok := customs.Structs[sig]; ok {
+			names[sig] = name
+		} else if q := qualified[sig]; q != "" {
+			parts := strings.Split(q, ".")
+			if len(sigsOf[parts[len(parts)-1]]) > 1 {
+				for i := range parts {
+					parts[i] = utils.Capitalise(parts[i])
+				}
+			} else {
+				parts = parts[len(parts)-1:]
+			}
+			names[sig] = utils.Capitalise(strings.Join(parts, ""))
+			derived[names[sig]] = append(derived[names[sig]], sig)
+		}
+	}
+	// Structs still called alike, such as Offer of different files, are numbered instead
+	for _, sigs := range derived {
+		if len(sigs) > 1 {
+			for _, sig := range sigs {
+				delete(names, sig)
+			}
+		}
+	}
+	return names, nil
+}
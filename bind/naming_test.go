@@ -0,0 +1,105 @@
+package bind
+
+import (
+	"testing"
+
+	"github.com/airbloc/solgen/bind/language"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMethodNames(t *testing.T) {
+	evmABI := exchangeABI(t)
+
+	for _, tc := range []struct {
+		name    string
+		customs Customs
+		names   map[string]string // Bound names by signature
+		err     string
+	}{
+		{
+			name: "overloads named after input types",
+			names: map[string]string{
+				"cancel((address,uint64))":   "Cancel",
+				"fee()":                      "Fee",
+				"offers()":                   "Offers",
+				"place((address,(uint256)))": "Place",
+				"settle((bytes8,bool))":      "SettleTuple",
+				"settle(bytes8[])":           "SettleBytes8Array",
+			},
+		},
+		{
+			name:    "overload left alone",
+			customs: Customs{Allow: []string{"settle*", "fee"}, Deny: []string{`settle(bytes8\[\])`}},
+			names: map[string]string{
+				"fee()":                 "Fee",
+				"settle((bytes8,bool))": "Settle",
+			},
+		},
+		{
+			name: "aliases normalized",
+			customs: Customs{
+				Allow:   []string{"settle"},
+				Aliases: map[string]string{"settle( bytes8[] )": "settleMany", "settle((bytes8,bool))": "settle_one"},
+			},
+			names: map[string]string{
+				"settle((bytes8,bool))": "SettleOne",
+				"settle(bytes8[])":      "SettleMany",
+			},
+		},
+		{
+			name:    "alias of an overload",
+			customs: Customs{Allow: []string{"settle"}, Aliases: map[string]string{"settle(bytes8[])": "SettleMany"}},
+			names: map[string]string{
+				"settle((bytes8,bool))": "SettleTuple",
+				"settle(bytes8[])":      "SettleMany",
+			},
+		},
+		{
+			name:    "collision",
+			customs: Customs{Aliases: map[string]string{"settle(bytes8[])": "fee"}},
+			err:     "several methods are named Fee (fee(), settle(bytes8[])), alias their signatures in customs",
+		},
+		{
+			name:    "collision with a method left alone",
+			customs: Customs{Deny: []string{"fee"}, Aliases: map[string]string{"settle(bytes8[])": "fee"}},
+			names: map[string]string{
+				"cancel((address,uint64))":   "Cancel",
+				"offers()":                   "Offers",
+				"place((address,(uint256)))": "Place",
+				"settle((bytes8,bool))":      "SettleTuple",
+				"settle(bytes8[])":           "Fee",
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			names, err := MethodNames(evmABI, tc.customs, language.Go)
+			if tc.err != "" {
+				assert.EqualError(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			bySig := make(map[string]string)
+			for key, name := range names {
+				bySig[evmABI.Methods[key].Sig()] = name
+			}
+			assert.Equal(t, tc.names, bySig)
+		})
+	}
+}
+
+func TestEventNames(t *testing.T) {
+	evmABI := exchangeABI(t)
+
+	names, err := EventNames(evmABI, Customs{}, language.Go)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"Placed": "Placed", "Settled": "Settled"}, names)
+
+	names, err = EventNames(evmABI, Customs{
+		Events:  Filter{Deny: []string{"Placed"}},
+		Aliases: map[string]string{"Settled(bytes8)": "settledById"},
+	}, language.Go)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"Settled": "SettledById"}, names)
+}
@@ -9,4 +9,16 @@ type Option struct {
 	Customs  Customs
 	Platform platform.Platform
 	Language language.Language
+	Modes    []Mode          // Modes to generate, every mode of Modes if empty
+	Packages map[Mode]string // Package names of each mode, the mode itself if empty
+
+	// ContractsImport is the import path of the contracts package, which managers refer to.
+	ContractsImport string
+}
+
+func (opt Option) packageOf(mode Mode) string {
+	if pkg, ok := opt.Packages[mode]; ok && pkg != "" {
+		return pkg
+	}
+	return string(mode)
 }
@@ -4,6 +4,10 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"go/token"
+	"math/big"
+	"sort"
+	"strings"
 	"unicode"
 
 	"github.com/airbloc/solgen/bind/language"
@@ -25,35 +29,42 @@ func parseContract(deployment deployment.Deployment, customs Customs, lang langu
 		events    = make(map[string]*template.Event)
 		structs   = make(map[string]*template.Struct)
 	)
-	for methodName, original := range evmABI.Methods {
-		if ok, exists := customs.Methods[methodName]; !exists || !ok {
+	methodNames, err := MethodNames(evmABI, customs, lang)
+	if err != nil {
+		return nil, err
+	}
+	eventNames, err := EventNames(evmABI, customs, lang)
+	if err != nil {
+		return nil, err
+	}
+	names, err := structNames(deployment.RawABI, customs)
+	if err != nil {
+		return nil, err
+	}
+
+	// Structs are numbered in the order they are met, so members are walked in order of their names
+	methodKeys := make([]string, 0, len(evmABI.Methods))
+	for key := range evmABI.Methods {
+		methodKeys = append(methodKeys, key)
+	}
+	sort.Strings(methodKeys)
+	eventKeys := make([]string, 0, len(evmABI.Events))
+	for key := range evmABI.Events {
+		eventKeys = append(eventKeys, key)
+	}
+	sort.Strings(eventKeys)
+
+	for _, methodName := range methodKeys {
+		original := evmABI.Methods[methodName]
+		if !customs.BindsMethod(methodName, original) {
 			continue
 		}
 
 		// Normalize the method for capital cases and non-anonymous inputs/outputs
 		normalized := original
-		normalized.Name = language.MethodNormalizer[lang](original.Name)
+		normalized.Name = methodNames[methodName]
 
-		normalized.Inputs = make([]abi.Argument, len(original.Inputs))
-		copy(normalized.Inputs, original.Inputs)
-		for j, input := range normalized.Inputs {
-			if input.Name == "" {
-				normalized.Inputs[j].Name = fmt.Sprintf("arg%d", j)
-			}
-
-			_, exist := structs[input.Type.String()]
-			if !exist {
-				switch input.Type.T {
-				case abi.TupleTy:
-					language.BindStructType[lang](input.Type, structs)
-				case abi.SliceTy:
-					// 예외 케이스 - tuple[]
-					if input.Type.Elem.T == abi.TupleTy {
-						language.BindStructType[lang](input.Type, structs)
-					}
-				}
-			}
-		}
+		normalized.Inputs = normalizeInputs(original.Inputs, structs, names, lang)
 		normalized.Outputs = make([]abi.Argument, len(original.Outputs))
 		copy(normalized.Outputs, original.Outputs)
 		for j, output := range normalized.Outputs {
@@ -61,32 +72,40 @@ func parseContract(deployment deployment.Deployment, customs Customs, lang langu
 				normalized.Outputs[j].Name = utils.Capitalise(output.Name)
 			}
 			if _, exist := structs[output.Type.String()]; output.Type.T == abi.TupleTy && !exist {
-				language.BindStructType[lang](output.Type, structs)
+				language.BindStructType[lang](output.Type, structs, names)
 			}
 		}
 		// Append the methods to the call or transact lists
 		if original.Const {
-			calls[original.Name] = &template.Method{
+			calls[normalized.Name] = &template.Method{
 				Original:   original,
 				Normalized: normalized,
 				Structured: utils.Structured(original.Outputs),
 			}
 		} else {
-			transacts[original.Name] = &template.Method{
+			transacts[normalized.Name] = &template.Method{
 				Original:   original,
 				Normalized: normalized,
 				Structured: utils.Structured(original.Outputs),
 			}
 		}
 	}
-	for _, original := range evmABI.Events {
+	// Normalize the constructor for non-anonymous inputs
+	constructor := evmABI.Constructor
+	constructor.Inputs = normalizeInputs(evmABI.Constructor.Inputs, structs, names, lang)
+	if lang == language.Go {
+		renameDeployInputs(constructor.Inputs)
+	}
+
+	for _, eventName := range eventKeys {
+		original := evmABI.Events[eventName]
 		// Skip anonymous events as they don't support explicit filtering
-		if original.Anonymous {
+		if original.Anonymous || !customs.BindsEvent(eventName, original) {
 			continue
 		}
 		// Normalize the event for capital cases and non-anonymous outputs
 		normalized := original
-		normalized.Name = language.MethodNormalizer[lang](original.Name)
+		normalized.Name = eventNames[eventName]
 
 		normalized.Inputs = make([]abi.Argument, len(original.Inputs))
 		copy(normalized.Inputs, original.Inputs)
@@ -97,12 +116,12 @@ func parseContract(deployment deployment.Deployment, customs Customs, lang langu
 					normalized.Inputs[j].Name = fmt.Sprintf("arg%d", j)
 				}
 				if _, exist := structs[input.Type.String()]; input.Type.T == abi.TupleTy && !exist {
-					language.BindStructType[lang](input.Type, structs)
+					language.BindStructType[lang](input.Type, structs, names)
 				}
 			}
 		}
 		// Append the event to the accumulator list
-		events[original.Name] = &template.Event{Original: original, Normalized: normalized}
+		events[normalized.Name] = &template.Event{Original: original, Normalized: normalized}
 	}
 
 	// There is no easy way to pass arbitrary java objects to the Go side.
@@ -110,26 +129,118 @@ func parseContract(deployment deployment.Deployment, customs Customs, lang langu
 		return nil, errors.New("java binding for tuple arguments is not supported yet")
 	}
 
-	for exp, strt := range structs {
-		if n, ok := customs.Structs[exp]; ok {
-			strt.Name = n
-		}
+	networks, err := parseNetworks(deployment.Networks)
+	if err != nil {
+		return nil, err
 	}
 
 	contract := &template.Contract{
 		Address:     deployment.Address.Hex(),
 		TxHash:      deployment.TxHash.Hex(),
-		CreatedAt:   common.BytesToHash(deployment.CreatedAt.Bytes()).Hex(),
-		Constructor: evmABI.Constructor,
+		CreatedAt:   hexBlock(deployment.CreatedAt),
+		Networks:    networks,
+		Constructor: constructor,
 		Calls:       calls,
 		Transacts:   transacts,
 		Events:      events,
 		Structs:     structs,
 	}
 
+	// Interfaces, abstract contracts and contracts linking libraries can't be deployed as is
+	if bin := strings.TrimPrefix(deployment.Bytecode, "0x"); bin != "" && !strings.Contains(bin, "__") {
+		contract.InputBin = "0x" + bin
+	}
+
 	return contract, nil
 }
 
+// normalizeInputs names anonymous inputs and binds the struct types of tuple inputs.
+func normalizeInputs(inputs []abi.Argument, structs map[string]*template.Struct, names map[string]string, lang language.Language) []abi.Argument {
+	normalized := make([]abi.Argument, len(inputs))
+	copy(normalized, inputs)
+	for j, input := range normalized {
+		if input.Name == "" {
+			normalized[j].Name = fmt.Sprintf("arg%d", j)
+		}
+
+		_, exist := structs[input.Type.String()]
+		if !exist {
+			switch input.Type.T {
+			case abi.TupleTy:
+				language.BindStructType[lang](input.Type, structs, names)
+			case abi.SliceTy:
+				// 예외 케이스 - tuple[]
+				if input.Type.Elem.T == abi.TupleTy {
+					language.BindStructType[lang](input.Type, structs, names)
+				}
+			}
+		}
+	}
+	return normalized
+}
+
+// deployNames are the identifiers of the Go deploy function, which constructor inputs must not shadow.
+var deployNames = map[string]bool{
+	"ctx": true, "opts": true, "backend": true, "evmABI": true, "err": true,
+	"deployOpts": true, "address": true, "tx": true, "receipt": true, "deployment": true,
+}
+
+// renameDeployInputs prefixes the constructor inputs named like Go keywords or identifiers
+// of the deploy function with underscores, until they are unique.
+func renameDeployInputs(inputs []abi.Argument) {
+	taken := make(map[string]bool)
+	for name := range deployNames {
+		taken[name] = true
+	}
+	for _, input := range inputs {
+		taken[input.Name] = true
+	}
+	for i, input := range inputs {
+		if !token.IsKeyword(input.Name) && !deployNames[input.Name] {
+			continue
+		}
+		name := "_" + input.Name
+		for taken[name] {
+			name = "_" + name
+		}
+		taken[name] = true
+		inputs[i].Name = name
+	}
+}
+
+// hexBlock encodes a creation block number as a hash.
+// Contracts which are only compiled have no creation block.
+func hexBlock(createdAt *big.Int) string {
+	if createdAt == nil {
+		createdAt = new(big.Int)
+	}
+	return common.BytesToHash(createdAt.Bytes()).Hex()
+}
+
+// parseNetworks converts the deployments keyed by chain ID, sorting them by chain ID.
+func parseNetworks(networks map[string]deployment.Network) ([]*template.Network, error) {
+	parsed := make([]*template.Network, 0, len(networks))
+	for key, network := range networks {
+		chainID, ok := new(big.Int).SetString(key, 10)
+		if !ok || chainID.Sign() < 0 {
+			return nil, fmt.Errorf("invalid chain ID %q of networks", key)
+		}
+		parsed = append(parsed, &template.Network{
+			ChainID:   chainID.String(),
+			Address:   network.Address.Hex(),
+			TxHash:    network.TxHash.Hex(),
+			CreatedAt: hexBlock(network.CreatedAt),
+		})
+	}
+
+	// Canonical decimals are ordered by length first
+	sort.Slice(parsed, func(i, j int) bool {
+		a, b := parsed[i].ChainID, parsed[j].ChainID
+		return len(a) < len(b) || len(a) == len(b) && a < b
+	})
+	return parsed, nil
+}
+
 func getContract(
 	deployment deployment.Deployment,
 	customs Customs,
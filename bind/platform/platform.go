@@ -1,5 +1,11 @@
 package platform
 
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
 type Platform string
 
 const (
@@ -12,6 +18,30 @@ var Imports = map[Platform]map[string]string{
 	Klaytn:   MergeImports(AirblocDependencies, KlaytnDependencies),
 }
 
+// Platforms returns every platform registered in Imports, sorted by name.
+func Platforms() []Platform {
+	plats := make([]Platform, 0, len(Imports))
+	for plat := range Imports {
+		plats = append(plats, plat)
+	}
+	sort.Slice(plats, func(i, j int) bool { return plats[i] < plats[j] })
+	return plats
+}
+
+// Parse resolves a platform name, failing if it is not registered in Imports.
+func Parse(name string) (Platform, error) {
+	plat := Platform(strings.ToLower(name))
+	if _, ok := Imports[plat]; ok {
+		return plat, nil
+	}
+
+	names := make([]string, 0, len(Imports))
+	for _, p := range Platforms() {
+		names = append(names, string(p))
+	}
+	return "", fmt.Errorf("unknown platform %q (supported: %s)", name, strings.Join(names, ", "))
+}
+
 func ManagerImports(plat Platform) map[string]string {
 	return MergeImports(map[string]string{
 		"wrappers": "github.com/airbloc/airbloc-go/bind/wrappers",
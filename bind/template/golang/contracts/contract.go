@@ -5,7 +5,9 @@ const Contract = `
 package {{.Package}}
 
 import (
-    "math/big"
+    {{if or .Contract.Networks .Contract.InputBin}}"context"
+    "fmt"
+    {{end}}"math/big"
     "strings"
 
     {{range $name, $import := .Imports}}{{$name}} "{{$import}}"
@@ -24,6 +26,19 @@ import (
         {{.Type}}CreatedAt = "{{.CreatedAt}}"
         {{.Type}}ABI = "{{.InputABI}}"
     )
+    {{- if .InputBin}}
+
+    // {{.Type}}Bin is the compiled bytecode used for deploying new contracts.
+    const {{.Type}}Bin = "{{.InputBin}}"
+    {{- end}}
+    {{- if .Networks}}
+
+    // {{.Type}}Networks are the deployments of {{.Type}} by chain ID.
+    var {{.Type}}Networks = map[string]struct{ Address, TxHash, CreatedAt string }{
+        {{range .Networks}}"{{.ChainID}}": {Address: "{{.Address}}", TxHash: "{{.TxHash}}", CreatedAt: "{{.CreatedAt}}"},
+        {{end}}
+    }
+    {{- end}}
 
     {{template "Caller" .}}
     {{template "Transactor" .}}
@@ -47,17 +62,45 @@ import (
                 return nil, err
             }
 
+            {{if $contract.Networks -}}
+            reader, ok := backend.(interface {
+                ChainID(ctx context.Context) (*big.Int, error)
+            })
+            if !ok {
+                return nil, fmt.Errorf("{{$contract.Type}}: backend does not report its chain ID")
+            }
+            chainID, err := reader.ChainID(context.Background())
+            if err != nil {
+                return nil, fmt.Errorf("{{$contract.Type}}: get chain ID: %v", err)
+            }
+            network, ok := {{$contract.Type}}Networks[chainID.String()]
+            if !ok {
+                return nil, fmt.Errorf("{{$contract.Type}} is not deployed to chain %s", chainID)
+            }
+
+            deployment = ablbind.NewDeployment(
+                common.HexToAddress(network.Address),
+                common.HexToHash(network.TxHash),
+                new(big.Int).SetBytes(common.HexToHash(network.CreatedAt).Bytes()),
+                evmABI,
+            )
+            {{- else -}}
             deployment = ablbind.NewDeployment(
                 common.HexToAddress({{$contract.Type}}Address),
                 common.HexToHash({{$contract.Type}}TxHash),
                 new(big.Int).SetBytes(common.HexToHash({{$contract.Type}}CreatedAt).Bytes()),
                 evmABI,
             )
+            {{- end}}
         }
 
+        return new{{$contract.Type}}Contract(deployment, backend), nil
+    }
+
+    func new{{$contract.Type}}Contract(deployment ablbind.Deployment, backend ablbind.ContractBackend) *{{$contract.Type}}Contract {
         base := ablbind.NewBoundContract(deployment.Address(), deployment.ParsedABI, "{{$contract.Type}}", backend)
 
-        contract := &{{$contract.Type}}Contract{
+        return &{{$contract.Type}}Contract{
             Deployment: deployment,
             client:    backend,
 
@@ -65,8 +108,42 @@ import (
             {{$contract.Type}}Transactor: &{{decapitalise $contract.Type}}Transactor{base, backend},
             {{$contract.Type}}Events: &{{decapitalise $contract.Type}}Events{base},
         }
+    }
+    {{- if $contract.InputBin}}
+
+    // Deploy{{$contract.Type}} deploys a new {{$contract.Type}} contract and binds an instance of it,
+    // created at the block mining the deployment.
+    func Deploy{{$contract.Type}}(
+        ctx context.Context,
+        opts *ablbind.TransactOpts,
+        backend ablbind.ContractBackend,
+        {{range $contract.Constructor.Inputs}}{{.Name}} {{bindtype .Type $structs}},
+    {{end}}) (*{{$contract.Type}}Contract, error) {
+        evmABI, err := abi.JSON(strings.NewReader({{$contract.Type}}ABI))
+        if err != nil {
+            return nil, err
+        }
+
+        if opts == nil {
+            opts = &ablbind.TransactOpts{}
+        }
+        deployOpts := opts.TransactOpts
+        deployOpts.Context = ctx
+        address, tx, _, err := bind.DeployContract(&deployOpts, evmABI, common.FromHex({{$contract.Type}}Bin), backend {{range $contract.Constructor.Inputs}}, {{.Name}}{{end}})
+        if err != nil {
+            return nil, err
+        }
+        receipt, err := bind.WaitMined(ctx, backend, tx)
+        if err != nil {
+            return nil, err
+        }
+        if receipt.Status != chainTypes.ReceiptStatusSuccessful {
+            return nil, fmt.Errorf("deploy {{$contract.Type}}: transaction %s failed", tx.Hash().Hex())
+        }
 
-        return contract, nil
+        deployment := ablbind.NewDeployment(address, tx.Hash(), receipt.BlockNumber, evmABI)
+        return new{{$contract.Type}}Contract(deployment, backend), nil
     }
+    {{- end}}
 {{end}}
 `
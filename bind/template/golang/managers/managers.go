@@ -13,6 +13,7 @@ import (
 
 {{$contract := .Contract}}
 {{$structs := .Contract.Structs}}
+{{$contracts := .ContractsPackage}}
 
 //go:generate mockgen -source {{toSnakeCase $contract.Type}}.go -destination ./mocks/mock_{{toSnakeCase $contract.Type}}.go -package mocks I{{$contract.Type}}Manager
 
@@ -21,7 +22,7 @@ type {{$contract.Type}}Manager interface {
     TxHash() common.Hash
     CreatedAt() *big.Int
 
-    contracts.{{$contract.Type}}Caller
+    {{$contracts}}.{{$contract.Type}}Caller
 
     {{range $contract.Transacts}}{{.Normalized.Name}}(
         ctx context.Context,
@@ -37,20 +38,20 @@ type {{$contract.Type}}Manager interface {
     )
     {{end}}
 
-    contracts.{{$contract.Type}}EventFilterer
-    contracts.{{$contract.Type}}EventWatcher
+    {{$contracts}}.{{$contract.Type}}EventFilterer
+    {{$contracts}}.{{$contract.Type}}EventWatcher
 }
 
 // {{decapitalise $contract.Type}}Manager is contract wrapper struct
 type {{decapitalise $contract.Type}}Manager struct {
-    *contracts.{{$contract.Type}}Contract
+    *{{$contracts}}.{{$contract.Type}}Contract
     client ablbind.ContractBackend
     log    logger.Logger
 }
 
 // New{{$contract.Type}}Manager makes new *{{decapitalise $contract.Type}}Manager struct
 func New{{$contract.Type}}Manager(backend ablbind.ContractBackend) ({{$contract.Type}}Manager, error) {
-    contract, err := contracts.New{{$contract.Type}}Contract(backend)
+    contract, err := {{$contracts}}.New{{$contract.Type}}Contract(backend)
     if err != nil {
         return nil, err
     }
@@ -22,9 +22,18 @@ import (
 
 // Data is the data structure required to fill the binding template.
 type Data struct {
-	Package  string            // Name of the package to place the generated file in
-	Imports  map[string]string // List of custom imports to push into this file
-	Contract *Contract         // List of contracts to generate into this file
+	Package          string            // Name of the package to place the generated file in
+	ContractsPackage string            // Name of the package holding contract bindings, referred by managers
+	Imports          map[string]string // List of custom imports to push into this file
+	Contract         *Contract         // List of contracts to generate into this file
+}
+
+// Network contains the deployment of a contract to a single chain.
+type Network struct {
+	ChainID   string
+	Address   string
+	TxHash    string
+	CreatedAt string
 }
 
 // Contract contains the data needed to generate an individual contract binding.
@@ -33,7 +42,9 @@ type Contract struct {
 	Address     string
 	TxHash      string
 	CreatedAt   string
+	Networks    []*Network         // Deployments by chain ID, sorted by chain ID
 	InputABI    string             // JSON ABI used as the input to generate the binding from
+	InputBin    string             // Optional EVM bytecode used to deploy new contracts
 	Constructor abi.Method         // Contract constructor for deploy parametrization
 	Calls       map[string]*Method // Contract calls that only read state data
 	Transacts   map[string]*Method // Contract calls that write state data
@@ -0,0 +1,6 @@
+// Package types is a stub of the airbloc-go types package.
+package types
+
+type ID [8]byte
+
+type DataId [20]byte
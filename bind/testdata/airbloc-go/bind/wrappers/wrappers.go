@@ -0,0 +1,2 @@
+// Package wrappers is a stub of the airbloc-go wrappers package.
+package wrappers
@@ -0,0 +1,3 @@
+module github.com/airbloc/airbloc-go
+
+go 1.13
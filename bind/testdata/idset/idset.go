@@ -0,0 +1,4 @@
+// Package idset is a package of types mapped by type maps in tests.
+package idset
+
+type ID [8]byte
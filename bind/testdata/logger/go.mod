@@ -0,0 +1,3 @@
+module github.com/airbloc/logger
+
+go 1.13
@@ -0,0 +1,10 @@
+// Package logger is a stub of the airbloc logger package.
+package logger
+
+type Logger interface {
+	Info(msg string, args ...interface{})
+}
+
+func New(name string) Logger {
+	return nil
+}
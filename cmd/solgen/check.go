@@ -0,0 +1,117 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"text/tabwriter"
+
+	"github.com/airbloc/solgen/bind/language"
+
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// Output statuses compared to the files on disk.
+const (
+	statusNew       = "new"
+	statusChanged   = "changed"
+	statusUnchanged = "unchanged"
+)
+
+// readCurrent reads the file on disk an output would be written to, nil if there is none.
+func readCurrent(out output) ([]byte, error) {
+	current, err := ioutil.ReadFile(out.Path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	return current, nil
+}
+
+// planOutputs prints the path, mode, contract and language of every output
+// with its status compared to disk, without touching the filesystem.
+// Outputs of no contract, such as the lock file, are printed with - as mode and contract.
+func planOutputs(w io.Writer, outputs []output, lang language.Language) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "STATUS\tMODE\tCONTRACT\tLANGUAGE\tPATH")
+	for _, out := range outputs {
+		current, err := readCurrent(out)
+		if err != nil {
+			return err
+		}
+
+		status := statusUnchanged
+		if current == nil {
+			status = statusNew
+		} else if !bytes.Equal(current, out.Code) {
+			status = statusChanged
+		}
+		mode, contract := string(out.Mode), out.Contract
+		if mode == "" {
+			mode = "-"
+		}
+		if contract == "" {
+			contract = "-"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", status, mode, contract, lang, out.Path)
+	}
+	return tw.Flush()
+}
+
+// checkOutputs compares rendered outputs with the files on disk and prints
+// a unified diff for each stale one. Missing files are diffed against nothing,
+// and leftover files which are not generated anymore are diffed to nothing.
+func checkOutputs(w io.Writer, outputs []output, leftovers []string) error {
+	stale := 0
+	for _, out := range outputs {
+		current, err := readCurrent(out)
+		if err != nil {
+			return err
+		}
+		if bytes.Equal(current, out.Code) {
+			continue
+		}
+		stale++
+
+		diff := difflib.UnifiedDiff{
+			A:        difflib.SplitLines(string(current)),
+			B:        difflib.SplitLines(string(out.Code)),
+			FromFile: out.Path,
+			ToFile:   out.Path + " (generated)",
+			Context:  3,
+		}
+		if current == nil {
+			diff.FromFile = "/dev/null"
+		}
+		if err := difflib.WriteUnifiedDiff(w, diff); err != nil {
+			return err
+		}
+	}
+
+	for _, path := range leftovers {
+		current, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		diff := difflib.UnifiedDiff{
+			A:        difflib.SplitLines(string(current)),
+			FromFile: path,
+			ToFile:   "/dev/null",
+			Context:  3,
+		}
+		if err := difflib.WriteUnifiedDiff(w, diff); err != nil {
+			return err
+		}
+	}
+
+	if stale > 0 || len(leftovers) > 0 {
+		msg := fmt.Sprintf("%d of %d generated files are out of date", stale, len(outputs))
+		if len(leftovers) > 0 {
+			msg += fmt.Sprintf(", %d files are not generated anymore", len(leftovers))
+		}
+		return errors.New(msg)
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/airbloc/solgen/bind"
+	"github.com/airbloc/solgen/bind/language"
+
+	"github.com/Flaque/filet"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckOutputs(t *testing.T) {
+	defer filet.CleanUp(t)
+	dir := filet.TmpDir(t, "")
+	filet.File(t, filepath.Join(dir, "token.go"), "package contracts\n")
+	filet.File(t, filepath.Join(dir, "exchange.go"), "package contracts\n\n// old\n")
+	filet.File(t, filepath.Join(dir, "old.go"), "package contracts\n")
+
+	token := output{Path: filepath.Join(dir, "token.go"), Code: []byte("package contracts\n")}
+	exchange := output{Path: filepath.Join(dir, "exchange.go"), Code: []byte("package contracts\n\n// new\n")}
+	users := output{Path: filepath.Join(dir, "users.go"), Code: []byte("package contracts\n")}
+
+	for _, tc := range []struct {
+		name      string
+		outputs   []output
+		leftovers []string
+		err       string
+		diffs     []string
+	}{
+		{name: "up to date", outputs: []output{token}},
+		{
+			name:    "changed",
+			outputs: []output{token, exchange},
+			err:     "1 of 2 generated files are out of date",
+			diffs:   []string{"+++ " + exchange.Path + " (generated)", "-// old", "+// new"},
+		},
+		{
+			name:    "missing",
+			outputs: []output{users},
+			err:     "1 of 1 generated files are out of date",
+			diffs:   []string{"--- /dev/null", "+++ " + users.Path + " (generated)"},
+		},
+		{
+			name:      "left over",
+			outputs:   []output{token},
+			leftovers: []string{filepath.Join(dir, "old.go")},
+			err:       "0 of 1 generated files are out of date, 1 files are not generated anymore",
+			diffs:     []string{"--- " + filepath.Join(dir, "old.go"), "+++ /dev/null", "-package contracts"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			w := new(bytes.Buffer)
+			err := checkOutputs(w, tc.outputs, tc.leftovers)
+			if tc.err == "" {
+				assert.NoError(t, err)
+				assert.Empty(t, w.String())
+				return
+			}
+			assert.EqualError(t, err, tc.err)
+			for _, diff := range tc.diffs {
+				assert.Contains(t, w.String(), diff)
+			}
+		})
+	}
+}
+
+func TestLeftovers(t *testing.T) {
+	defer filet.CleanUp(t)
+	dir := filet.TmpDir(t, "")
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "contracts"), os.ModePerm))
+	for _, name := range []string{"token.go", "old_token.go", "mock.go", "README.md"} {
+		filet.File(t, filepath.Join(dir, "contracts", name), "")
+	}
+
+	j := &job{
+		Target: Target{Config: Config{OutputPath: dir}},
+		option: bind.Option{Language: language.Go, Modes: []bind.Mode{bind.Contract, bind.Manager}},
+	}
+	outputs := []output{{Path: j.outputPath("Token", bind.Contract)}, {Path: j.outputPath("Token", bind.Manager)}}
+
+	leftovers, err := j.leftovers(outputs, []string{"Mock"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(dir, "contracts", "old_token.go")}, leftovers)
+}
@@ -1,14 +1,163 @@
 package main
 
-import "github.com/kelseyhightower/envconfig"
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/airbloc/solgen/bind/platform"
+	"github.com/airbloc/solgen/deployment"
+
+	"github.com/kelseyhightower/envconfig"
+)
+
+const defaultOutputPath = "./build"
+
+// paths is a list of paths, which may be given as a single string in project files.
+type paths []string
+
+// stdinReads counts the paths reading the deployment from stdin.
+func (p paths) stdinReads() int {
+	reads := 0
+	for _, path := range p {
+		if path == deployment.StdinPath {
+			reads++
+		}
+	}
+	return reads
+}
+
+func (p *paths) UnmarshalJSON(data []byte) error {
+	var path string
+	if err := json.Unmarshal(data, &path); err == nil {
+		*p = paths{path}
+		return nil
+	}
+	return json.Unmarshal(data, (*[]string)(p))
+}
+
+func (p *paths) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var path string
+	if err := unmarshal(&path); err == nil {
+		*p = paths{path}
+		return nil
+	}
+	return unmarshal((*[]string)(p))
+}
 
 type Config struct {
-	DeploymentPath string `envconfig:"deployment_path"`
-	OptionPath     string `envconfig:"option_path"`
-	OutputPath     string `envconfig:"output_path"`
+	DeploymentPaths paths  `envconfig:"deployment_path" json:"deployment" yaml:"deployment"` // Merged in order, later ones taking precedence
+	AllowOverride   bool   `envconfig:"allow_override" json:"allow_override" yaml:"allow_override"`
+	OptionPath      string `envconfig:"option_path" json:"options" yaml:"options"`
+	OutputPath      string `envconfig:"output_path" json:"output" yaml:"output"`
+	Platform        string `envconfig:"platform" json:"platform" yaml:"platform"`
+	Network         string `envconfig:"network" json:"network" yaml:"network"`
+
+	// Fetching of deployment urls. The token is only read from the environment.
+	HTTPHeaders  []string      `envconfig:"http_headers" json:"http_headers" yaml:"http_headers"` // "Name: value" headers
+	HTTPToken    string        `envconfig:"http_token" json:"-" yaml:"-"`                         // Bearer token sent as Authorization header
+	HTTPTimeout  time.Duration `envconfig:"http_timeout" json:"-" yaml:"-"`
+	HTTPAttempts int           `envconfig:"http_attempts" json:"-" yaml:"-"`
+	HTTPCache    string        `envconfig:"http_cache" json:"http_cache" yaml:"http_cache"` // Directory caching fetched deployments
 }
 
 func NewConfig() (config Config) {
 	envconfig.MustProcess("solgen", &config)
 	return
 }
+
+// LoadDeployments loads the deployments of the config.
+func (c Config) LoadDeployments() (deployment.Deployments, error) {
+	fetcher, err := c.fetcher()
+	if err != nil {
+		return nil, err
+	}
+	loader := &deployment.Loader{Network: c.Network, Fetcher: fetcher, AllowOverride: c.AllowOverride}
+	return loader.LoadAll(c.DeploymentPaths...)
+}
+
+func (c Config) fetcher() (*deployment.Fetcher, error) {
+	header := make(http.Header)
+	for _, h := range c.HTTPHeaders {
+		colon := strings.Index(h, ":")
+		if colon <= 0 {
+			return nil, fmt.Errorf("invalid http header %q, expected \"Name: value\"", h)
+		}
+		header.Add(strings.TrimSpace(h[:colon]), strings.TrimSpace(h[colon+1:]))
+	}
+	if c.HTTPToken != "" {
+		header.Set("Authorization", "Bearer "+c.HTTPToken)
+	}
+
+	return &deployment.Fetcher{
+		Header:   header,
+		Timeout:  c.HTTPTimeout,
+		Attempts: c.HTTPAttempts,
+		CacheDir: c.HTTPCache,
+		ReadOnly: cmdDryRun || cmdCheck, // dry runs and checks write nothing, not even the cache
+		Logf:     log.Printf,
+	}, nil
+}
+
+// Merge returns the config with every field overridden by the non-empty fields of override.
+func (c Config) Merge(override Config) Config {
+	if len(c.DeploymentPaths) == 0 || len(override.DeploymentPaths) != 0 {
+		c.DeploymentPaths = override.DeploymentPaths
+	}
+	c.AllowOverride = c.AllowOverride || override.AllowOverride
+	if c.OptionPath == "" || override.OptionPath != "" {
+		c.OptionPath = override.OptionPath
+	}
+	if c.OutputPath == "" || override.OutputPath != "" {
+		c.OutputPath = override.OutputPath
+	}
+	if c.Platform == "" || override.Platform != "" {
+		c.Platform = override.Platform
+	}
+	if c.Network == "" || override.Network != "" {
+		c.Network = override.Network
+	}
+	if len(c.HTTPHeaders) == 0 || len(override.HTTPHeaders) != 0 {
+		c.HTTPHeaders = override.HTTPHeaders
+	}
+	if c.HTTPToken == "" || override.HTTPToken != "" {
+		c.HTTPToken = override.HTTPToken
+	}
+	if c.HTTPTimeout == 0 || override.HTTPTimeout != 0 {
+		c.HTTPTimeout = override.HTTPTimeout
+	}
+	if c.HTTPAttempts == 0 || override.HTTPAttempts != 0 {
+		c.HTTPAttempts = override.HTTPAttempts
+	}
+	if c.HTTPCache == "" || override.HTTPCache != "" {
+		c.HTTPCache = override.HTTPCache
+	}
+	return c
+}
+
+// WithDefaults fills the fields left empty with their default values.
+func (c Config) WithDefaults() Config {
+	if c.OutputPath == "" {
+		c.OutputPath = defaultOutputPath
+	}
+	if c.Platform == "" {
+		c.Platform = string(platform.Klaytn)
+	}
+	if c.HTTPTimeout == 0 {
+		c.HTTPTimeout = deployment.DefaultTimeout
+	}
+	if c.HTTPAttempts == 0 {
+		c.HTTPAttempts = deployment.DefaultAttempts
+	}
+	if c.HTTPCache == "" {
+		if dir, err := os.UserCacheDir(); err == nil {
+			c.HTTPCache = filepath.Join(dir, "solgen")
+		}
+	}
+	return c
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/airbloc/solgen/deployment"
+)
+
+// filter selects contracts by glob patterns of their names, as in path.Match.
+// Every contract is selected by default if there is no Only pattern.
+type filter struct {
+	Only    []string
+	Exclude []string
+}
+
+func (f filter) validate() error {
+	for _, pattern := range append(f.Only, f.Exclude...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("contract pattern %q: %v", pattern, err)
+		}
+	}
+	return nil
+}
+
+func matchAny(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (f filter) match(name string) bool {
+	if len(f.Only) > 0 && !matchAny(f.Only, name) {
+		return false
+	}
+	return !matchAny(f.Exclude, name)
+}
+
+// filters combines the filters of several sources, such as the project target, the options file
+// and the command line. A contract is selected only if every filter selects it, so that the Only
+// patterns of a source narrow down the contracts of the others instead of adding to them.
+type filters []filter
+
+func (fs filters) validate() error {
+	for _, f := range fs {
+		if err := f.validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (fs filters) match(name string) bool {
+	for _, f := range fs {
+		if !f.match(name) {
+			return false
+		}
+	}
+	return true
+}
+
+// apply returns the selected deployments, and the sorted names of the filtered-out ones.
+func (fs filters) apply(deployments deployment.Deployments) (deployment.Deployments, []string) {
+	selected := make(deployment.Deployments)
+	var filtered []string
+	for name, contract := range deployments {
+		if fs.match(name) {
+			selected[name] = contract
+		} else {
+			filtered = append(filtered, name)
+		}
+	}
+	sort.Strings(filtered)
+	return selected, filtered
+}
+
+// filterDeployments applies the filters, reporting the filtered-out contracts in verbose mode.
+// It returns the selected deployments and the sorted names of the filtered-out ones.
+func filterDeployments(deployments deployment.Deployments, fs ...filter) (deployment.Deployments, []string, error) {
+	if err := filters(fs).validate(); err != nil {
+		return nil, nil, err
+	}
+
+	selected, filtered := filters(fs).apply(deployments)
+	if cmdVerbose && len(filtered) > 0 {
+		log.Printf("filtered out %d contracts: %s", len(filtered), strings.Join(filtered, ", "))
+	}
+	return selected, filtered, nil
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/airbloc/solgen/deployment"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilterMatch(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		filter   filter
+		selected []string
+		filtered []string
+	}{
+		{name: "everything", selected: []string{"Token", "TokenMock", "Migrations"}},
+		{
+			name:     "only",
+			filter:   filter{Only: []string{"Token*"}},
+			selected: []string{"Token", "TokenMock"},
+			filtered: []string{"Migrations", "Exchange"},
+		},
+		{
+			name:     "exclude",
+			filter:   filter{Exclude: []string{"*Mock", "Migrations"}},
+			selected: []string{"Token", "Exchange"},
+			filtered: []string{"TokenMock", "Migrations"},
+		},
+		{
+			name:     "exclude wins over only",
+			filter:   filter{Only: []string{"Token*"}, Exclude: []string{"*Mock"}},
+			selected: []string{"Token"},
+			filtered: []string{"TokenMock", "Exchange"},
+		},
+		{
+			name:     "several only patterns",
+			filter:   filter{Only: []string{"Token", "Ex?hange"}},
+			selected: []string{"Token", "Exchange"},
+			filtered: []string{"TokenMock"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			for _, name := range tc.selected {
+				assert.True(t, tc.filter.match(name), name)
+			}
+			for _, name := range tc.filtered {
+				assert.False(t, tc.filter.match(name), name)
+			}
+		})
+	}
+}
+
+func TestFilterValidate(t *testing.T) {
+	assert.NoError(t, filter{Only: []string{"Token*"}, Exclude: []string{"[A-Z]*Mock"}}.validate())
+	assert.EqualError(t, filter{Exclude: []string{"[Mock"}}.validate(), `contract pattern "[Mock": syntax error in pattern`)
+	assert.Error(t, filters{{}, {Only: []string{"Token["}}}.validate())
+}
+
+func TestFilterDeployments(t *testing.T) {
+	deployments := deployment.Deployments{
+		"Token":      {},
+		"TokenMock":  {},
+		"Exchange":   {},
+		"Migrations": {},
+	}
+	project := filter{Only: []string{"Token*", "Exchange"}, Exclude: []string{"Migrations"}}
+	options := filter{Exclude: []string{"*Mock"}}
+
+	for _, tc := range []struct {
+		name     string
+		filters  []filter
+		selected []string
+		filtered []string
+	}{
+		{name: "no filter", selected: []string{"Exchange", "Migrations", "Token", "TokenMock"}},
+		{
+			name:     "excludes add up",
+			filters:  []filter{project, options},
+			selected: []string{"Exchange", "Token"},
+			filtered: []string{"Migrations", "TokenMock"},
+		},
+		{
+			name:     "only narrows down",
+			filters:  []filter{project, options, {Only: []string{"Token"}}},
+			selected: []string{"Token"},
+			filtered: []string{"Exchange", "Migrations", "TokenMock"},
+		},
+		{
+			name:     "only out of other only",
+			filters:  []filter{project, {Only: []string{"Migrations"}}},
+			filtered: []string{"Exchange", "Migrations", "Token", "TokenMock"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			selected, filtered, err := filterDeployments(deployments, tc.filters...)
+			require.NoError(t, err)
+			var names []string
+			for name := range selected {
+				names = append(names, name)
+			}
+			assert.ElementsMatch(t, tc.selected, names)
+			assert.Equal(t, tc.filtered, filtered)
+		})
+	}
+
+	_, _, err := filterDeployments(deployments, project, filter{Only: []string{"["}})
+	assert.Error(t, err)
+}
@@ -0,0 +1,463 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/airbloc/solgen/bind"
+	"github.com/airbloc/solgen/bind/language"
+	"github.com/airbloc/solgen/bind/platform"
+	"github.com/airbloc/solgen/deployment"
+	"github.com/airbloc/solgen/utils"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+var (
+	cmdModes           []string
+	cmdContractsImport string
+	cmdCheck           bool
+	cmdDryRun          bool
+	cmdJobs            int
+	cmdKeepGoing       bool
+
+	goCmd = &cobra.Command{
+		Use:     "go",
+		Short:   "Generate Go contract bindings and managers",
+		PreRunE: requireDeployment,
+		RunE:    func(cmd *cobra.Command, args []string) error { return runBind(language.Go) },
+	}
+)
+
+func init() {
+	addBindFlags(goCmd.Flags())
+	addWatchFlags(goCmd.Flags())
+}
+
+func addBindFlags(flags *pflag.FlagSet) {
+	flags.StringVar(&cmdConfig.Platform, "platform", "", "target platform of generated binds (default \""+string(platform.Klaytn)+"\")")
+	flags.StringSliceVar(&cmdModes, "mode", nil, "modes to generate (default all of contracts, managers)")
+	flags.StringVar(&cmdContractsImport, "contracts-import", "", "import path of the generated contracts package, which managers refer to")
+	flags.BoolVar(&cmdCheck, "check", false, "fail if generated binds differ from the ones in output path, without writing")
+	addGenerateFlags(flags)
+}
+
+func addGenerateFlags(flags *pflag.FlagSet) {
+	flags.BoolVar(&cmdDryRun, "dry-run", false, "print the files to be written and whether they change, without writing")
+	flags.IntVarP(&cmdJobs, "jobs", "j", runtime.NumCPU(), "number of contracts generated in parallel")
+	flags.BoolVar(&cmdKeepGoing, "keep-going", false, "exit successfully even if some binds failed to generate")
+}
+
+// output is a rendered bind waiting to be written to Path.
+type output struct {
+	Path     string
+	Contract string
+	Mode     bind.Mode
+	Code     []byte
+}
+
+// failure is a bind of a contract which failed to generate.
+// Mode is empty if the contract failed before rendering any mode.
+type failure struct {
+	Contract string
+	Mode     bind.Mode
+	Err      error
+}
+
+func (f failure) String() string {
+	if f.Mode == "" {
+		return fmt.Sprintf("%s: %v", f.Contract, f.Err)
+	}
+	return fmt.Sprintf("%s (%s): %v", f.Contract, f.Mode, f.Err)
+}
+
+// reportFailures prints a summary of the failures. Unless --keep-going is given,
+// it returns an error if there is any failure.
+func reportFailures(w io.Writer, target Target, failures []failure) error {
+	if len(failures) == 0 {
+		return nil
+	}
+
+	fmt.Fprintf(w, "%s: %d binds failed to generate:\n", target, len(failures))
+	for _, f := range failures {
+		fmt.Fprintln(w, "  "+f.String())
+	}
+	if cmdKeepGoing {
+		return nil
+	}
+	return fmt.Errorf("%d binds failed to generate", len(failures))
+}
+
+func parseModes(names []string) ([]bind.Mode, error) {
+	if len(names) == 0 {
+		return bind.Modes, nil
+	}
+
+	modes := make([]bind.Mode, len(names))
+	for i, name := range names {
+		mode, err := bind.ParseMode(name)
+		if err != nil {
+			return nil, err
+		}
+		modes[i] = mode
+	}
+	return modes, nil
+}
+
+func parsePackages(names map[string]string) (map[bind.Mode]string, error) {
+	packages := make(map[bind.Mode]string)
+	for name, pkg := range names {
+		mode, err := bind.ParseMode(name)
+		if err != nil {
+			return nil, err
+		}
+		packages[mode] = pkg
+	}
+	return packages, nil
+}
+
+func containsMode(modes []bind.Mode, mode bind.Mode) bool {
+	for _, m := range modes {
+		if m == mode {
+			return true
+		}
+	}
+	return false
+}
+
+// job is a target resolved into bind options.
+type job struct {
+	Target
+	option bind.Option
+}
+
+func newJob(target Target) (*job, error) {
+	if cmdDryRun && cmdCheck {
+		return nil, errors.New("--dry-run can't be used with --check")
+	}
+	plat, err := platform.Parse(target.Platform)
+	if err != nil {
+		return nil, err
+	}
+	lang, err := bind.ParseLanguage(target.Language)
+	if err != nil {
+		return nil, err
+	}
+	modes, err := parseModes(target.Modes)
+	if err != nil {
+		return nil, err
+	}
+	packages, err := parsePackages(target.Packages)
+	if err != nil {
+		return nil, err
+	}
+	if err := target.filters().validate(); err != nil {
+		return nil, err
+	}
+	if target.ContractsImport == "" && containsMode(modes, bind.Manager) {
+		log.Printf("warning: %s: managers refer to the contracts package, whose import path is not configured", target)
+	}
+
+	return &job{
+		Target: target,
+		option: bind.Option{
+			Platform: plat,
+			Language: lang,
+			Modes:    modes,
+			Packages: packages,
+
+			ContractsImport: target.ContractsImport,
+		},
+	}, nil
+}
+
+// load reads the deployments selected by the filters of the target, the names of the filtered-out ones,
+// and the customs of the target. Customs of the target replace the ones of its options file,
+// and the type map of the target is merged into the type map of every contract.
+func (j *job) load() (deployment.Deployments, []string, map[string]bind.Customs, error) {
+	deployments, err := j.LoadDeployments()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	opts, err := loadOptions(j.OptionPath)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	for name, custom := range j.Customs {
+		opts.Customs[name] = custom
+	}
+	reportUnknownCustoms(j.Target, deployments, opts.Customs)
+
+	deployments, filtered, err := filterDeployments(deployments, append(j.filters(), opts.filter)...)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if len(j.TypeMap) > 0 {
+		for name := range deployments {
+			custom := opts.Customs[name]
+			custom.TypeMap = j.TypeMap.Merge(custom.TypeMap)
+			opts.Customs[name] = custom
+		}
+	}
+	return deployments, filtered, opts.Customs, nil
+}
+
+// reportUnknownCustoms warns about customs of contracts missing from the deployments,
+// and about method and event names or patterns of customs matching nothing, which are likely typos.
+func reportUnknownCustoms(target Target, deployments deployment.Deployments, customs map[string]bind.Customs) {
+	names := make([]string, 0, len(customs))
+	for name := range customs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		contract, ok := deployments[name]
+		if !ok {
+			log.Printf("%s: customs of unknown contract %s", target, name)
+			continue
+		}
+		if unknown := customs[name].Unknown(contract.EvmABI); len(unknown) > 0 {
+			log.Printf("%s: %s: unknown %s in customs", target, name, strings.Join(unknown, ", "))
+		}
+	}
+}
+
+// outputPath returns the path the bind of a contract in a mode is written to.
+func (j *job) outputPath(name string, mode bind.Mode) string {
+	return filepath.Clean(filepath.Join(j.OutputPath, string(mode), utils.ToSnakeCase(name)+language.FileExtension[j.option.Language]))
+}
+
+// leftovers lists the files of the mode directories which are not generated anymore,
+// such as binds of removed or renamed contracts. Binds of the kept contracts,
+// filtered out or failed to generate, are not left over.
+func (j *job) leftovers(outputs []output, kept []string) ([]string, error) {
+	generated := make(map[string]bool)
+	for _, out := range outputs {
+		generated[out.Path] = true
+	}
+	for _, name := range kept {
+		for _, mode := range j.option.Modes {
+			generated[j.outputPath(name, mode)] = true
+		}
+	}
+
+	var leftovers []string
+	for _, mode := range j.option.Modes {
+		dir := filepath.Join(j.OutputPath, string(mode))
+		files, err := ioutil.ReadDir(dir)
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		for _, file := range files {
+			path := filepath.Clean(filepath.Join(dir, file.Name()))
+			if !file.IsDir() && filepath.Ext(path) == language.FileExtension[j.option.Language] && !generated[path] {
+				leftovers = append(leftovers, path)
+			}
+		}
+	}
+	sort.Strings(leftovers)
+	return leftovers, nil
+}
+
+// bindContract renders every mode of a contract.
+func (j *job) bindContract(name string, contract deployment.Deployment, customs bind.Customs) ([]output, []failure) {
+	opt := j.option
+	opt.Customs = customs
+	codes, err := bind.Bind(name, contract, opt)
+	if err != nil {
+		f := failure{Contract: name, Err: err}
+		if modeErr, ok := err.(*bind.ModeError); ok {
+			f.Mode, f.Err = modeErr.Mode, modeErr.Err
+		}
+		return nil, []failure{f}
+	}
+
+	var (
+		outputs  []output
+		failures []failure
+	)
+	for _, mode := range opt.Modes {
+		code, ok := codes[mode]
+		if !ok {
+			failures = append(failures, failure{Contract: name, Mode: mode, Err: errors.New("not generated")})
+			continue
+		}
+
+		outputs = append(outputs, output{
+			Path:     j.outputPath(name, mode),
+			Contract: name,
+			Mode:     mode,
+			Code:     code,
+		})
+	}
+	return outputs, failures
+}
+
+// generate renders every contract of deployments in memory, up to --jobs contracts at once.
+// The outputs are sorted by path, and the failures by contract name.
+func (j *job) generate(deployments deployment.Deployments, customs map[string]bind.Customs) ([]output, []failure) {
+	names := make([]string, 0, len(deployments))
+	for name := range deployments {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	type result struct {
+		outputs  []output
+		failures []failure
+	}
+	var (
+		results = make([]result, len(names))
+		indices = make(chan int)
+		wg      sync.WaitGroup
+	)
+	workers := cmdJobs
+	if workers < 1 {
+		workers = 1
+	}
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indices {
+				name := names[i]
+				results[i].outputs, results[i].failures = j.bindContract(name, deployments[name], customs[name])
+			}
+		}()
+	}
+	for i := range names {
+		indices <- i
+	}
+	close(indices)
+	wg.Wait()
+
+	var (
+		outputs  []output
+		failures []failure
+	)
+	for _, r := range results {
+		outputs = append(outputs, r.outputs...)
+		failures = append(failures, r.failures...)
+	}
+	sort.Slice(outputs, func(i, j int) bool { return outputs[i].Path < outputs[j].Path })
+	return outputs, failures
+}
+
+// write writes the outputs to disk. Files which failed to be written are returned as failures.
+func (j *job) write(outputs []output) ([]failure, error) {
+	for _, mode := range j.option.Modes {
+		if err := os.MkdirAll(filepath.Join(j.OutputPath, string(mode)), os.ModePerm); err != nil {
+			return nil, err
+		}
+	}
+
+	var failures []failure
+	for _, out := range outputs {
+		if err := ioutil.WriteFile(out.Path, out.Code, os.ModePerm); err != nil {
+			failures = append(failures, failure{Contract: out.Contract, Mode: out.Mode, Err: err})
+		}
+	}
+	return failures, nil
+}
+
+func (j *job) run() error {
+	deployments, filtered, customs, err := j.load()
+	if err != nil {
+		return err
+	}
+
+	outputs, failures := j.generate(deployments, customs)
+
+	previous, err := loadLock(j.lockPath())
+	if err != nil {
+		return err
+	}
+	next, err := j.lock(previous, deployments, filtered, customs, outputs, failures)
+	if err != nil {
+		return err
+	}
+	reportABIChanges(os.Stderr, j.Target, previous, next)
+
+	if cmdDryRun || cmdCheck {
+		lockOut, err := j.lockOutput(next)
+		if err != nil {
+			return err
+		}
+		planned := append(append([]output{}, outputs...), lockOut)
+
+		if cmdDryRun {
+			if err := planOutputs(os.Stdout, planned, j.option.Language); err != nil {
+				return err
+			}
+			return reportFailures(os.Stderr, j.Target, failures)
+		}
+
+		kept := append([]string{}, filtered...)
+		for _, f := range failures {
+			kept = append(kept, f.Contract)
+		}
+		leftovers, err := j.leftovers(outputs, kept)
+		if err != nil {
+			return err
+		}
+		// failures are reported even if outputs are out of date, as they are likely the cause
+		checkErr := checkOutputs(os.Stdout, planned, leftovers)
+		failureErr := reportFailures(os.Stderr, j.Target, failures)
+		if checkErr != nil && failureErr != nil {
+			return fmt.Errorf("%v; %v", checkErr, failureErr)
+		} else if checkErr != nil {
+			return checkErr
+		}
+		return failureErr
+	}
+
+	writeFailures, err := j.write(outputs)
+	if err != nil {
+		return err
+	}
+	failures = append(failures, writeFailures...)
+	if next, err = j.lock(previous, deployments, filtered, customs, outputs, failures); err != nil {
+		return err
+	}
+	if err := j.writeLock(next); err != nil {
+		return err
+	}
+	return reportFailures(os.Stderr, j.Target, failures)
+}
+
+func runBind(lang language.Language) error {
+	target := Target{
+		Config:          config,
+		Language:        string(lang),
+		Modes:           cmdModes,
+		ContractsImport: cmdContractsImport,
+		Only:            cmdFilter.Only,
+		Exclude:         cmdFilter.Exclude,
+	}
+	if cmdWatch {
+		return watchTargets(target)
+	}
+	return runTarget(target)
+}
+
+func runTarget(target Target) error {
+	j, err := newJob(target)
+	if err != nil {
+		return err
+	}
+	return j.run()
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/airbloc/solgen/bind"
+	"github.com/airbloc/solgen/bind/language"
+	"github.com/airbloc/solgen/bind/platform"
+	"github.com/airbloc/solgen/deployment"
+
+	"github.com/Flaque/filet"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const generateDeployment = `{
+  "Token": {
+    "address": "0x0000000000000000000000000000000000000001",
+    "abi": [{"type": "function", "name": "balanceOf", "stateMutability": "view",
+      "inputs": [{"name": "owner", "type": "address"}], "outputs": [{"name": "", "type": "uint256"}]}]
+  },
+  "Registry": {
+    "address": "0x0000000000000000000000000000000000000002",
+    "abi": [{"type": "function", "name": "register", "stateMutability": "nonpayable",
+      "inputs": [{"name": "id", "type": "bytes8"}], "outputs": []}]
+  },
+  "Vault": {
+    "address": "0x0000000000000000000000000000000000000003",
+    "abi": [{"type": "function", "name": "deposit", "stateMutability": "payable",
+      "inputs": [{"name": "amount", "type": "uint256"}], "outputs": []}]
+  }
+}`
+
+// brokenCustoms map uint256 to a type which is not valid Go, so that binds of contracts using it fail.
+var brokenCustoms = bind.Customs{TypeMap: bind.TypeMap{"uint256": {Type: "*big.Int)"}}}
+
+func testJob(t *testing.T, out string) *job {
+	j, err := newJob(Target{
+		Config:          Config{OutputPath: out, Platform: string(platform.Ethereum)},
+		Language:        string(language.Go),
+		ContractsImport: "example.com/contracts",
+	})
+	require.NoError(t, err)
+	return j
+}
+
+func TestGenerateJobs(t *testing.T) {
+	deployments, err := deployment.GetDeploymentsFromReader(strings.NewReader(generateDeployment))
+	require.NoError(t, err)
+	customs := map[string]bind.Customs{"Vault": brokenCustoms}
+	j := testJob(t, "build")
+
+	defer func(jobs int) { cmdJobs = jobs }(cmdJobs)
+	cmdJobs = 1
+	expected, expectedFailures := j.generate(deployments, customs)
+
+	var paths []string
+	for _, out := range expected {
+		paths = append(paths, out.Path)
+	}
+	assert.Equal(t, []string{
+		filepath.Join("build", "contracts", "registry.go"),
+		filepath.Join("build", "contracts", "token.go"),
+		filepath.Join("build", "managers", "registry.go"),
+		filepath.Join("build", "managers", "token.go"),
+	}, paths)
+	require.Len(t, expectedFailures, 1)
+	assert.Equal(t, "Vault", expectedFailures[0].Contract)
+	assert.Equal(t, bind.Contract, expectedFailures[0].Mode)
+
+	for _, jobs := range []int{0, 2, 3, 16} {
+		cmdJobs = jobs
+		outputs, failures := j.generate(deployments, customs)
+		assert.Equal(t, expected, outputs, "outputs of %d jobs", jobs)
+		assert.Equal(t, expectedFailures, failures, "failures of %d jobs", jobs)
+	}
+}
+
+func TestReportFailures(t *testing.T) {
+	defer func(keepGoing bool) { cmdKeepGoing = keepGoing }(cmdKeepGoing)
+	target := Target{Name: "ethereum"}
+	failures := []failure{
+		{Contract: "Token", Err: errors.New("invalid abi")},
+		{Contract: "Vault", Mode: bind.Manager, Err: errors.New("not generated")},
+	}
+
+	for _, tc := range []struct {
+		name      string
+		failures  []failure
+		keepGoing bool
+		err       string
+		report    string
+	}{
+		{name: "no failure"},
+		{
+			name:     "failures",
+			failures: failures,
+			err:      "2 binds failed to generate",
+			report:   "ethereum: 2 binds failed to generate:\n  Token: invalid abi\n  Vault (managers): not generated\n",
+		},
+		{
+			name:      "keep going",
+			failures:  failures,
+			keepGoing: true,
+			report:    "ethereum: 2 binds failed to generate:\n  Token: invalid abi\n  Vault (managers): not generated\n",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cmdKeepGoing = tc.keepGoing
+			w := new(bytes.Buffer)
+			err := reportFailures(w, target, tc.failures)
+			if tc.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.err)
+			}
+			assert.Equal(t, tc.report, w.String())
+		})
+	}
+}
+
+func TestRunCheckWithFailures(t *testing.T) {
+	defer filet.CleanUp(t)
+	dir := filet.TmpDir(t, "")
+	path := filepath.Join(dir, "deployment.json")
+	filet.File(t, path, generateDeployment)
+
+	defer func(check bool) { cmdCheck = check }(cmdCheck)
+	cmdCheck = true
+	j := testJob(t, filepath.Join(dir, "build"))
+	j.DeploymentPaths = paths{path}
+	j.Customs = map[string]bind.Customs{"Vault": brokenCustoms}
+
+	err := j.run()
+	assert.EqualError(t, err, "5 of 5 generated files are out of date; 1 binds failed to generate")
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/spf13/cobra"
+)
+
+var inspectCmd = &cobra.Command{
+	Use:   "inspect [contracts...]",
+	Short: "Print contracts found in the deployment",
+	Long: "Prints the address, transaction and ABI summary of the given contracts, or every contract if none given.\n" +
+		"Every method and event signature is printed in verbose mode.",
+	PreRunE: requireDeployment,
+	RunE:    func(cmd *cobra.Command, args []string) error { return runInspect(args) },
+}
+
+func runInspect(names []string) error {
+	deployments, err := config.LoadDeployments()
+	if err != nil {
+		return err
+	}
+
+	if len(names) == 0 {
+		if deployments, _, err = filterDeployments(deployments, cmdFilter); err != nil {
+			return err
+		}
+		for name := range deployments {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+	}
+
+	for _, name := range names {
+		contract, ok := deployments[name]
+		if !ok {
+			return fmt.Errorf("contract %s not found in deployment", name)
+		}
+
+		var calls, transacts, methods, events []string
+		for _, method := range contract.EvmABI.Methods {
+			if method.Const {
+				calls = append(calls, method.Sig())
+			} else {
+				transacts = append(transacts, method.Sig())
+			}
+		}
+		for _, event := range contract.EvmABI.Events {
+			events = append(events, event.Sig())
+		}
+		methods = append(methods, calls...)
+		methods = append(methods, transacts...)
+		sort.Strings(methods)
+		sort.Strings(events)
+
+		fmt.Println(name)
+		fmt.Println("  address:   ", contract.Address.Hex())
+		fmt.Println("  tx hash:   ", contract.TxHash.Hex())
+		fmt.Println("  created at:", contract.CreatedAt)
+		fmt.Printf("  methods:    %d (%d calls, %d transacts)\n", len(methods), len(calls), len(transacts))
+		if cmdVerbose {
+			for _, sig := range methods {
+				fmt.Println("    " + sig)
+			}
+		}
+		fmt.Printf("  events:     %d\n", len(events))
+		if cmdVerbose {
+			for _, sig := range events {
+				fmt.Println("    " + sig)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,291 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/airbloc/solgen/bind"
+	"github.com/airbloc/solgen/bind/language"
+	"github.com/airbloc/solgen/deployment"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+)
+
+// lockFile is the name of the lock file written in the output path.
+const lockFile = "solgen.lock"
+
+// lock records the inputs and outputs of the last generation of a target.
+type lock struct {
+	Version   string                     `json:"version"`
+	Contracts map[string]*lockedContract `json:"contracts"`
+}
+
+// lockedContract records the generation of a single contract.
+type lockedContract struct {
+	ABI     string            `json:"abi"`     // Hash of the normalized ABI
+	Methods map[string]string `json:"methods"` // Signatures of bound methods with their outputs, by bound name
+	Events  map[string]string `json:"events"`  // Signatures of bound events, by bound name
+	Customs bind.Customs      `json:"customs"`
+	Outputs map[string]string `json:"outputs"` // Hashes of the output files, by path relative to the output path
+}
+
+func hashOf(data []byte) string {
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:])
+}
+
+// normalizedABIHash hashes the ABI regardless of the order of its entries and fields.
+func normalizedABIHash(parsed []map[string]interface{}) (string, error) {
+	entries := make([]string, len(parsed))
+	for i, entry := range parsed {
+		encoded, err := json.Marshal(entry)
+		if err != nil {
+			return "", err
+		}
+		entries[i] = string(encoded)
+	}
+	sort.Strings(entries)
+	return hashOf([]byte(strings.Join(entries, "\n"))), nil
+}
+
+func methodSignature(method abi.Method) string {
+	outputs := make([]string, len(method.Outputs))
+	for i, output := range method.Outputs {
+		outputs[i] = output.Type.String()
+	}
+	return fmt.Sprintf("%s returns (%s)", method.Sig(), strings.Join(outputs, ","))
+}
+
+func eventSignature(event abi.Event) string {
+	inputs := make([]string, len(event.Inputs))
+	for i, input := range event.Inputs {
+		inputs[i] = input.Type.String()
+		if input.Indexed {
+			inputs[i] += " indexed"
+		}
+	}
+	return fmt.Sprintf("%s(%s)", event.RawName, strings.Join(inputs, ","))
+}
+
+func lockContract(contract deployment.Deployment, customs bind.Customs, lang language.Language) (*lockedContract, error) {
+	abiHash, err := normalizedABIHash(contract.ParsedABI)
+	if err != nil {
+		return nil, err
+	}
+	methodNames, err := bind.MethodNames(contract.EvmABI, customs, lang)
+	if err != nil {
+		return nil, err
+	}
+	eventNames, err := bind.EventNames(contract.EvmABI, customs, lang)
+	if err != nil {
+		return nil, err
+	}
+
+	locked := &lockedContract{
+		ABI:     abiHash,
+		Methods: make(map[string]string),
+		Events:  make(map[string]string),
+		Customs: customs,
+		Outputs: make(map[string]string),
+	}
+	for key, name := range methodNames {
+		locked.Methods[name] = methodSignature(contract.EvmABI.Methods[key])
+	}
+	for key, name := range eventNames {
+		locked.Events[name] = eventSignature(contract.EvmABI.Events[key])
+	}
+	return locked, nil
+}
+
+func loadLock(path string) (*lock, error) {
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	l := new(lock)
+	if err := json.Unmarshal(data, l); err != nil {
+		return nil, fmt.Errorf("parse %s: %v", path, err)
+	}
+	return l, nil
+}
+
+// lockPath returns the path of the lock file of the job.
+func (j *job) lockPath() string {
+	return filepath.Join(j.OutputPath, lockFile)
+}
+
+// lock records the deployments and customs of the job, with the hashes of the outputs generated from them.
+// Output hashes of contracts not generated this time, such as unchanged ones in watch mode, are kept from previous,
+// unless their mode is not generated anymore. Contracts which failed to generate or were filtered out
+// are kept as previously locked.
+func (j *job) lock(
+	previous *lock,
+	deployments deployment.Deployments,
+	filtered []string,
+	customs map[string]bind.Customs,
+	outputs []output,
+	failures []failure,
+) (*lock, error) {
+	kept := make(map[string]bool)
+	for _, f := range failures {
+		kept[f.Contract] = true
+	}
+	for _, name := range filtered {
+		kept[name] = true
+	}
+
+	next := &lock{Version: version, Contracts: make(map[string]*lockedContract)}
+	for name := range kept {
+		if previous != nil && previous.Contracts[name] != nil {
+			next.Contracts[name] = previous.Contracts[name]
+		}
+	}
+	for name, contract := range deployments {
+		if kept[name] {
+			continue
+		}
+		locked, err := lockContract(contract, customs[name], j.option.Language)
+		if err != nil {
+			return nil, fmt.Errorf("lock %s: %v", name, err)
+		}
+		if previous != nil && previous.Contracts[name] != nil {
+			for _, mode := range j.option.Modes {
+				path, err := j.relativePath(j.outputPath(name, mode))
+				if err != nil {
+					return nil, err
+				}
+				if hash, ok := previous.Contracts[name].Outputs[path]; ok {
+					locked.Outputs[path] = hash
+				}
+			}
+		}
+		next.Contracts[name] = locked
+	}
+
+	for _, out := range outputs {
+		locked, ok := next.Contracts[out.Contract]
+		if !ok || kept[out.Contract] {
+			continue
+		}
+		path, err := j.relativePath(out.Path)
+		if err != nil {
+			return nil, err
+		}
+		locked.Outputs[path] = hashOf(out.Code)
+	}
+	return next, nil
+}
+
+// relativePath returns the path of an output relative to the output path, as recorded in lock files.
+func (j *job) relativePath(path string) (string, error) {
+	rel, err := filepath.Rel(j.OutputPath, path)
+	if err != nil {
+		return "", err
+	}
+	return filepath.ToSlash(rel), nil
+}
+
+// lockOutput renders the lock file as an output, so that dry runs and checks see it like the binds.
+func (j *job) lockOutput(l *lock) (output, error) {
+	data, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return output{}, err
+	}
+	return output{Path: j.lockPath(), Code: append(data, '\n')}, nil
+}
+
+func (j *job) writeLock(l *lock) error {
+	out, err := j.lockOutput(l)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(j.OutputPath, os.ModePerm); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(out.Path, out.Code, 0644)
+}
+
+// reportABIChanges prints the contracts whose ABI changed since the previous lock.
+// Removed and re-typed methods and events are flagged as breaking changes.
+func reportABIChanges(w io.Writer, target Target, previous, next *lock) {
+	if previous == nil {
+		return
+	}
+
+	var lines []string
+	for _, name := range sortedContracts(previous, next) {
+		before, after := previous.Contracts[name], next.Contracts[name]
+		switch {
+		case after == nil:
+			lines = append(lines, fmt.Sprintf("  %s: removed (breaking)", name))
+		case before == nil:
+			lines = append(lines, fmt.Sprintf("  %s: added", name))
+		case before.ABI != after.ABI:
+			lines = append(lines, fmt.Sprintf("  %s: ABI changed", name))
+			lines = append(lines, diffMembers("method", before.Methods, after.Methods)...)
+			lines = append(lines, diffMembers("event", before.Events, after.Events)...)
+		}
+	}
+	if len(lines) == 0 {
+		return
+	}
+	if previous.Version != version {
+		lines = append(lines, fmt.Sprintf("  (previously generated by solgen %s)", previous.Version))
+	}
+	fmt.Fprintf(w, "%s: ABI changes since the last generation:\n%s\n", target, strings.Join(lines, "\n"))
+}
+
+// diffMembers describes the methods or events added, removed or re-typed.
+func diffMembers(kind string, before, after map[string]string) []string {
+	names := make([]string, 0, len(before)+len(after))
+	for name := range before {
+		names = append(names, name)
+	}
+	for name := range after {
+		if _, ok := before[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var lines []string
+	for _, name := range names {
+		old, existed := before[name]
+		sig, exists := after[name]
+		switch {
+		case !exists:
+			lines = append(lines, fmt.Sprintf("    breaking: removed %s %s", kind, old))
+		case !existed:
+			lines = append(lines, fmt.Sprintf("    added %s %s", kind, sig))
+		case old != sig:
+			lines = append(lines, fmt.Sprintf("    breaking: re-typed %s %s, was %s", kind, sig, old))
+		}
+	}
+	return lines
+}
+
+func sortedContracts(locks ...*lock) []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, l := range locks {
+		for name := range l.Contracts {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,203 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/airbloc/solgen/bind"
+	"github.com/airbloc/solgen/bind/language"
+	"github.com/airbloc/solgen/deployment"
+
+	"github.com/Flaque/filet"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizedABIHash(t *testing.T) {
+	transfer := map[string]interface{}{"type": "function", "name": "transfer", "inputs": []interface{}{}}
+	approve := map[string]interface{}{"type": "function", "name": "approve", "inputs": []interface{}{}}
+	reordered := map[string]interface{}{"inputs": []interface{}{}, "name": "transfer", "type": "function"}
+	transferFrom := map[string]interface{}{"type": "function", "name": "transferFrom", "inputs": []interface{}{}}
+
+	expected, err := normalizedABIHash([]map[string]interface{}{transfer, approve})
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		name    string
+		abi     []map[string]interface{}
+		changed bool
+	}{
+		{name: "same", abi: []map[string]interface{}{transfer, approve}},
+		{name: "entries reordered", abi: []map[string]interface{}{approve, transfer}},
+		{name: "fields reordered", abi: []map[string]interface{}{reordered, approve}},
+		{name: "entry renamed", abi: []map[string]interface{}{transferFrom, approve}, changed: true},
+		{name: "entry removed", abi: []map[string]interface{}{transfer}, changed: true},
+		{name: "entry added", abi: []map[string]interface{}{transfer, approve, transferFrom}, changed: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			hash, err := normalizedABIHash(tc.abi)
+			require.NoError(t, err)
+			assert.Equal(t, tc.changed, hash != expected)
+		})
+	}
+}
+
+func TestDiffMembers(t *testing.T) {
+	for _, tc := range []struct {
+		name          string
+		before, after map[string]string
+		lines         []string
+	}{
+		{name: "unchanged", before: map[string]string{"Get": "get() returns (uint256)"}, after: map[string]string{"Get": "get() returns (uint256)"}},
+		{name: "added", after: map[string]string{"Set": "set(uint256) returns ()"}, lines: []string{"    added method set(uint256) returns ()"}},
+		{
+			name:   "removed",
+			before: map[string]string{"Set": "set(uint256) returns ()"},
+			lines:  []string{"    breaking: removed method set(uint256) returns ()"},
+		},
+		{
+			name:   "re-typed",
+			before: map[string]string{"Set": "set(uint256) returns ()"},
+			after:  map[string]string{"Set": "set(uint64) returns ()"},
+			lines:  []string{"    breaking: re-typed method set(uint64) returns (), was set(uint256) returns ()"},
+		},
+		{
+			name:   "sorted by name",
+			before: map[string]string{"B": "b() returns ()", "C": "c() returns ()"},
+			after:  map[string]string{"A": "a() returns ()", "C": "c(bool) returns ()"},
+			lines: []string{
+				"    added method a() returns ()",
+				"    breaking: removed method b() returns ()",
+				"    breaking: re-typed method c(bool) returns (), was c() returns ()",
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.lines, diffMembers("method", tc.before, tc.after))
+		})
+	}
+}
+
+func TestReportABIChanges(t *testing.T) {
+	token := &lockedContract{ABI: "1", Methods: map[string]string{"Get": "get() returns (uint256)"}}
+	changedToken := &lockedContract{ABI: "2", Methods: map[string]string{"Get": "get() returns (uint64)"}}
+	vault := &lockedContract{ABI: "3"}
+	target := Target{Name: "ethereum"}
+
+	for _, tc := range []struct {
+		name           string
+		previous, next *lock
+		report         string
+	}{
+		{name: "first generation", next: &lock{Version: version, Contracts: map[string]*lockedContract{"Token": token}}},
+		{
+			name:     "unchanged",
+			previous: &lock{Version: version, Contracts: map[string]*lockedContract{"Token": token}},
+			next:     &lock{Version: version, Contracts: map[string]*lockedContract{"Token": token}},
+		},
+		{
+			name:     "changes",
+			previous: &lock{Version: version, Contracts: map[string]*lockedContract{"Token": token, "Vault": vault}},
+			next:     &lock{Version: version, Contracts: map[string]*lockedContract{"Exchange": vault, "Token": changedToken}},
+			report: "ethereum: ABI changes since the last generation:\n" +
+				"  Exchange: added\n" +
+				"  Token: ABI changed\n" +
+				"    breaking: re-typed method get() returns (uint64), was get() returns (uint256)\n" +
+				"  Vault: removed (breaking)\n",
+		},
+		{
+			name:     "older version",
+			previous: &lock{Version: "v0.0.1", Contracts: map[string]*lockedContract{}},
+			next:     &lock{Version: version, Contracts: map[string]*lockedContract{"Vault": vault}},
+			report: "ethereum: ABI changes since the last generation:\n" +
+				"  Vault: added\n" +
+				"  (previously generated by solgen v0.0.1)\n",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			w := new(bytes.Buffer)
+			reportABIChanges(w, target, tc.previous, tc.next)
+			assert.Equal(t, tc.report, w.String())
+		})
+	}
+}
+
+func TestJobLock(t *testing.T) {
+	deployments, err := deployment.GetDeploymentsFromReader(strings.NewReader(generateDeployment))
+	require.NoError(t, err)
+	j := &job{
+		Target: Target{Config: Config{OutputPath: "build"}},
+		option: bind.Option{Language: language.Go, Modes: []bind.Mode{bind.Contract, bind.Manager}},
+	}
+	outputs := []output{
+		{Path: j.outputPath("Token", bind.Contract), Contract: "Token", Mode: bind.Contract, Code: []byte("token")},
+		{Path: j.outputPath("Token", bind.Manager), Contract: "Token", Mode: bind.Manager, Code: []byte("token manager")},
+		{Path: j.outputPath("Vault", bind.Contract), Contract: "Vault", Mode: bind.Contract, Code: []byte("vault")},
+		{Path: j.outputPath("Vault", bind.Manager), Contract: "Vault", Mode: bind.Manager, Code: []byte("vault manager")},
+	}
+	previous, err := j.lock(nil, deployments, nil, nil, outputs, nil)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"contracts/token.go": hashOf([]byte("token")),
+		"managers/token.go":  hashOf([]byte("token manager")),
+	}, previous.Contracts["Token"].Outputs)
+
+	// Registry is filtered out, Vault fails and Token is generated as contracts only
+	j.option.Modes = []bind.Mode{bind.Contract}
+	selected := deployment.Deployments{"Token": deployments["Token"], "Vault": deployments["Vault"]}
+	failures := []failure{{Contract: "Vault", Mode: bind.Contract}}
+	next, err := j.lock(previous, selected, []string{"Registry"}, nil, outputs[:1], failures)
+	require.NoError(t, err)
+
+	assert.Equal(t, previous.Contracts["Registry"], next.Contracts["Registry"])
+	assert.Equal(t, previous.Contracts["Vault"], next.Contracts["Vault"])
+	assert.Equal(t, map[string]string{"contracts/token.go": hashOf([]byte("token"))}, next.Contracts["Token"].Outputs)
+
+	w := new(bytes.Buffer)
+	reportABIChanges(w, Target{}, previous, next)
+	assert.Empty(t, w.String())
+}
+
+func TestRunLockFile(t *testing.T) {
+	defer filet.CleanUp(t)
+	dir := filet.TmpDir(t, "")
+	path := filepath.Join(dir, "deployment.json")
+	filet.File(t, path, generateDeployment)
+
+	defer func(check bool) { cmdCheck = check }(cmdCheck)
+	cmdCheck = false
+	j := testJob(t, filepath.Join(dir, "build"))
+	j.DeploymentPaths = paths{path}
+	require.NoError(t, j.run())
+
+	cmdCheck = true
+	assert.NoError(t, j.run())
+
+	// A stale lock file fails the check like a stale bind
+	locked, err := ioutil.ReadFile(j.lockPath())
+	require.NoError(t, err)
+	stale := strings.Replace(string(locked), version, "v0.0.0", 1)
+	require.NoError(t, ioutil.WriteFile(j.lockPath(), []byte(stale), 0644))
+	assert.EqualError(t, j.run(), "1 of 7 generated files are out of date")
+
+	lockOut, err := j.lockOutput(&lock{Version: version})
+	require.NoError(t, err)
+	w := new(bytes.Buffer)
+	require.NoError(t, planOutputs(w, []output{lockOut}, language.Go))
+	assert.Regexp(t, `changed +- +- +golang +`+regexp.QuoteMeta(j.lockPath()), w.String())
+}
+
+func TestProjectSharedOutputPath(t *testing.T) {
+	defer filet.CleanUp(t)
+	dir := filet.TmpDir(t, "")
+	path := filepath.Join(dir, "solgen.yaml")
+	filet.File(t, path, "targets:\n"+
+		"  - {name: ethereum, deployment: ./eth.json, output: ./build}\n"+
+		"  - {name: klaytn, deployment: ./klay.json, output: build/}\n")
+
+	assert.EqualError(t, runProject(path), "targets ethereum and klaytn share the output path "+filepath.Join(dir, "build"))
+}
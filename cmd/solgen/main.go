@@ -1,117 +1,148 @@
 package main
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io/ioutil"
-	"log"
 	"os"
-	"path"
-	"path/filepath"
 
 	"github.com/airbloc/solgen/bind"
-	"github.com/airbloc/solgen/bind/language"
-	"github.com/airbloc/solgen/bind/platform"
-	"github.com/airbloc/solgen/deployment"
-	"github.com/airbloc/solgen/utils"
 
 	"github.com/spf13/cobra"
 )
 
+// version of solgen, recorded in lock files.
+const version = "v0.1.5"
+
 var (
-	config    = NewConfig()
+	envConfig = NewConfig()
 	cmdConfig = Config{}
+	overrides Config // merged from envConfig and cmdConfig, overrides project targets
+	config    Config // overrides with defaults, used by single generator commands
+
+	projectPath string
+	cmdVerbose  bool
+	cmdFilter   filter
 
 	rootCmd = &cobra.Command{
 		Use:   "solgen",
 		Short: "Golang ABI bind generator for Airbloc",
 		Long: "Solgen is a tool for generate solidity binds.\n" +
-			"This application helps to generate go/proto bind of solidity.",
-		Version: "v0.1.5",
-		Run:     func(cmd *cobra.Command, args []string) { run() },
+			"This application helps to generate go/proto bind of solidity.\n\n" +
+			"Without a command, every target of the project file (solgen.json or solgen.yaml)\n" +
+			"in the working directory is generated.",
+		Version:      version,
+		SilenceUsage: true,
+		RunE:         func(cmd *cobra.Command, args []string) error { return runRoot(cmd) },
 	}
 )
 
 func init() {
 	cobra.OnInitialize(initConfig)
 
+	rootCmd.AddCommand(goCmd, protoCmd, inspectCmd)
+
 	flags := rootCmd.PersistentFlags()
-	flags.StringVar(&cmdConfig.DeploymentPath, "deployment", "", "path of deployment (json)")
+	flags.StringArrayVar((*[]string)(&cmdConfig.DeploymentPaths), "deployment", nil, "path of deployment (json) or solc output, - for stdin, or truffle/hardhat/foundry build directory; "+
+		"can be repeated, later ones taking precedence")
+	flags.BoolVar(&cmdConfig.AllowOverride, "allow-override", false, "let later deployments redefine contracts of earlier ones")
+	flags.StringVar(&cmdConfig.Network, "network", "", "network picked from artifacts deployed to several networks")
+	flags.StringArrayVar(&cmdConfig.HTTPHeaders, "header", nil, "\"Name: value\" header sent when fetching deployment urls, can be repeated")
+	flags.DurationVar(&cmdConfig.HTTPTimeout, "http-timeout", 0, "timeout of fetching deployment urls (default 30s)")
+	flags.IntVar(&cmdConfig.HTTPAttempts, "http-attempts", 0, "attempts of fetching deployment urls, retried with backoff (default 3)")
+	flags.StringVar(&cmdConfig.HTTPCache, "http-cache", "", "directory caching deployment urls for offline use (default user cache directory)")
 	flags.StringVar(&cmdConfig.OptionPath, "opt", "", "path of custom bind options")
-	flags.StringVar(&cmdConfig.OutputPath, "out", "./build", "path of generated output")
+	flags.StringVar(&cmdConfig.OutputPath, "out", "", "path of generated output (default \""+defaultOutputPath+"\")")
+	flags.StringArrayVar(&cmdFilter.Only, "only", nil, "glob pattern of contracts to generate, can be repeated (default all)")
+	flags.StringArrayVar(&cmdFilter.Exclude, "exclude", nil, "glob pattern of contracts not to generate, can be repeated")
+	flags.BoolVarP(&cmdVerbose, "verbose", "v", false, "print verbose messages")
+
+	rootCmd.Flags().StringVar(&projectPath, "project", "", "path of project file (default solgen.json or solgen.yaml in working directory)")
+	rootCmd.Flags().BoolVar(&cmdCheck, "check", false, "fail if generated binds differ from the ones in output paths, without writing")
+	addGenerateFlags(rootCmd.Flags())
+	addWatchFlags(rootCmd.Flags())
 }
 
 func initConfig() {
 	// merge config
-	if config.DeploymentPath == "" || cmdConfig.DeploymentPath != "" {
-		config.DeploymentPath = cmdConfig.DeploymentPath
-	}
-	if config.OptionPath == "" || cmdConfig.OptionPath != "" {
-		config.OptionPath = cmdConfig.OptionPath
-	}
-	if config.OutputPath == "" || cmdConfig.OutputPath != "" {
-		config.OutputPath = cmdConfig.OutputPath
-	}
+	overrides = envConfig.Merge(cmdConfig)
+	config = overrides.WithDefaults()
+}
 
-	if config.DeploymentPath == "" {
-		panic("deployment path needed")
+func runRoot(cmd *cobra.Command) error {
+	path := projectPath
+	if path == "" {
+		wd, err := os.Getwd()
+		if err != nil {
+			return err
+		}
+		if path, err = findProject(wd); err != nil {
+			return err
+		}
+	}
+	if path == "" {
+		return errors.New("no solgen.json/solgen.yaml found; use `solgen go` to generate from flags")
 	}
+	return runProject(path)
 }
 
-func run() {
-	deployments, err := deployment.GetDeploymentsFrom(config.DeploymentPath)
-	if err != nil {
-		panic(err)
+// requireDeployment fails commands reading deployments when no path is given,
+// or when the standard input is read more than once.
+func requireDeployment(cmd *cobra.Command, args []string) error {
+	if len(config.DeploymentPaths) == 0 {
+		return errors.New("deployment path needed")
 	}
+	if config.DeploymentPaths.stdinReads() > 1 {
+		return errors.New("the deployment can be read from stdin only once")
+	}
+	return nil
+}
 
-	customs := make(map[string]bind.Customs)
-	if config.OptionPath != "" {
-		opt, err := ioutil.ReadFile(config.OptionPath)
-		if err != nil {
-			panic(err)
-		}
+// options is the content of a custom bind options file. Besides customs keyed by contract
+// names, the file may hold "only" and "exclude" arrays of contract name patterns.
+type options struct {
+	Customs map[string]bind.Customs
+	filter
+}
 
-		if err := json.Unmarshal(opt, &customs); err != nil {
-			panic(err)
-		}
+func loadOptions(path string) (*options, error) {
+	opts := &options{Customs: make(map[string]bind.Customs)}
+	if path == "" {
+		return opts, nil
 	}
 
-	for _, mode := range bind.Modes {
-		if err := os.MkdirAll(path.Clean(path.Join(config.OutputPath, string(mode))), os.ModePerm); err != nil {
-			panic(err)
-		}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	entries := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, err
 	}
 
-	for name, contract := range deployments {
-		codes, err := bind.Bind(
-			name, contract,
-			bind.Option{
-				Customs:  customs[name],
-				Platform: platform.Klaytn,
-				Language: language.Go,
-			},
-		)
-		if err != nil {
-			log.Println(err)
-			continue
+	for name, entry := range entries {
+		isArray := bytes.HasPrefix(bytes.TrimSpace(entry), []byte("["))
+		switch {
+		case name == "only" && isArray:
+			err = json.Unmarshal(entry, &opts.Only)
+		case name == "exclude" && isArray:
+			err = json.Unmarshal(entry, &opts.Exclude)
+		default:
+			var customs bind.Customs
+			err = json.Unmarshal(entry, &customs)
+			opts.Customs[name] = customs
 		}
-
-		for _, mode := range bind.Modes {
-			code, ok := codes[mode]
-			if !ok {
-				log.Println(mode, "not found")
-				continue
-			}
-
-			filename := filepath.Clean(filepath.Join(config.OutputPath, string(mode), utils.ToSnakeCase(name)+".go"))
-			if err := ioutil.WriteFile(filename, code, os.ModePerm); err != nil {
-				log.Println(err)
-			}
+		if err != nil {
+			return nil, fmt.Errorf("%s of %s: %v", name, path, err)
 		}
 	}
+	return opts, nil
 }
 
 func main() {
 	if err := rootCmd.Execute(); err != nil {
-		panic(err)
+		os.Exit(1)
 	}
 }
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/airbloc/solgen/bind"
+	"github.com/airbloc/solgen/bind/language"
+	"github.com/airbloc/solgen/deployment"
+)
+
+// projectFiles are the names of project files looked up from the working directory, in order.
+var projectFiles = []string{"solgen.json", "solgen.yaml", "solgen.yml"}
+
+// Project describes every target generated by a single solgen invocation.
+type Project struct {
+	Targets []Target `json:"targets" yaml:"targets"`
+}
+
+// Target is a single generation of binds from a deployment source.
+type Target struct {
+	Config          `yaml:",inline"`
+	Name            string                  `json:"name" yaml:"name"`
+	Language        string                  `json:"language" yaml:"language"`
+	Modes           []string                `json:"modes" yaml:"modes"`
+	Packages        map[string]string       `json:"packages" yaml:"packages"`                 // Package names keyed by mode
+	ContractsImport string                  `json:"contracts_import" yaml:"contracts_import"` // Import path of the contracts package, which managers refer to
+	Customs         map[string]bind.Customs `json:"customs" yaml:"customs"`                   // Overrides the customs of the options file
+	TypeMap         bind.TypeMap            `json:"type_map" yaml:"type_map"`                 // Type map of every contract, overridden by the ones of customs
+	Only            []string                `json:"only" yaml:"only"`                         // Glob patterns of contracts to generate
+	Exclude         []string                `json:"exclude" yaml:"exclude"`                   // Glob patterns of contracts not to generate
+
+	cmdFilter filter // Filter of the command line, narrowing down the contracts of the target
+}
+
+func (t Target) filters() filters {
+	return filters{{Only: t.Only, Exclude: t.Exclude}, t.cmdFilter}
+}
+
+func (t Target) String() string {
+	if t.Name != "" {
+		return t.Name
+	}
+	return fmt.Sprintf("%s (%s)", t.OutputPath, t.Language)
+}
+
+// findProject looks up a project file in the given directory.
+// It returns an empty path without error if there is none.
+func findProject(dir string) (string, error) {
+	for _, name := range projectFiles {
+		path := filepath.Join(dir, name)
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		} else if !os.IsNotExist(err) {
+			return "", err
+		}
+	}
+	return "", nil
+}
+
+func loadProject(path string) (*Project, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	project := new(Project)
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.UnmarshalStrict(data, project)
+	default:
+		err = json.Unmarshal(data, project)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parse project %s: %v", path, err)
+	}
+
+	dir := filepath.Dir(path)
+	for i, target := range project.Targets {
+		if target.Language == "" {
+			target.Language = string(language.Go)
+		}
+		target.Config = target.Config.relativeTo(dir)
+		project.Targets[i] = target
+	}
+	return project, nil
+}
+
+// relativeTo resolves the relative paths of a project file against its directory.
+// Deployment urls and the standard input are left as is.
+func (c Config) relativeTo(dir string) Config {
+	resolve := func(path string) string {
+		if path == "" || filepath.IsAbs(path) {
+			return path
+		}
+		return filepath.Join(dir, path)
+	}
+	if len(c.DeploymentPaths) > 0 {
+		resolved := make(paths, len(c.DeploymentPaths))
+		for i, path := range c.DeploymentPaths {
+			if path == deployment.StdinPath || deployment.IsURL(path) {
+				resolved[i] = path
+			} else {
+				resolved[i] = resolve(path)
+			}
+		}
+		c.DeploymentPaths = resolved
+	}
+	c.OptionPath = resolve(c.OptionPath)
+	c.OutputPath = resolve(c.OutputPath)
+	c.HTTPCache = resolve(c.HTTPCache)
+	return c
+}
+
+// projectTargets loads the targets of a project file, overridden by the environment and persistent flags.
+func projectTargets(path string) ([]Target, error) {
+	project, err := loadProject(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(project.Targets) == 0 {
+		return nil, fmt.Errorf("no targets in project %s", path)
+	}
+
+	targets := make([]Target, len(project.Targets))
+	outputs := make(map[string]Target)
+	stdinReads := 0
+	for i, target := range project.Targets {
+		target.Config = target.Config.Merge(overrides).WithDefaults()
+		target.cmdFilter = cmdFilter
+		if len(target.DeploymentPaths) == 0 {
+			return nil, fmt.Errorf("target %s: deployment path needed", target)
+		}
+		// Each target locks and checks the whole output path, which can't be shared
+		out := filepath.Clean(target.OutputPath)
+		if other, ok := outputs[out]; ok {
+			return nil, fmt.Errorf("targets %s and %s share the output path %s", other, target, out)
+		}
+		outputs[out] = target
+		stdinReads += target.DeploymentPaths.stdinReads()
+		targets[i] = target
+	}
+	if stdinReads > 1 {
+		return nil, errors.New("the deployment can be read from stdin only once")
+	}
+	return targets, nil
+}
+
+func runProject(path string) error {
+	targets, err := projectTargets(path)
+	if err != nil {
+		return err
+	}
+
+	if cmdWatch {
+		return watchTargets(targets...)
+	}
+	failed := 0
+	for _, target := range targets {
+		if err := runTarget(target); err != nil {
+			fmt.Fprintf(os.Stderr, "target %s: %v\n", target, err)
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d targets failed", failed, len(targets))
+	}
+	return nil
+}
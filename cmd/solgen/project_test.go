@@ -0,0 +1,195 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Flaque/filet"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunRootWithoutProject(t *testing.T) {
+	defer filet.CleanUp(t)
+	dir := filet.TmpDir(t, "")
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(dir))
+	defer os.Chdir(wd)
+
+	// Flags without a command used to print the help and exit successfully, generating nothing
+	defer func(path string) { projectPath = path }(projectPath)
+	projectPath = ""
+	assert.EqualError(t, runRoot(rootCmd), "no solgen.json/solgen.yaml found; use `solgen go` to generate from flags")
+}
+
+func TestLoadProject(t *testing.T) {
+	defer filet.CleanUp(t)
+	dir := filet.TmpDir(t, "")
+
+	for _, tc := range []struct {
+		name    string
+		file    string
+		content string
+		targets []Target
+		err     string
+	}{
+		{
+			name:    "json",
+			file:    "solgen.json",
+			content: `{"targets": [{"name": "ethereum", "deployment": "deployment.json", "options": "options.json", "output": "build", "language": "golang", "modes": ["contracts"]}]}`,
+			targets: []Target{{
+				Config: Config{
+					DeploymentPaths: paths{filepath.Join(dir, "deployment.json")},
+					OptionPath:      filepath.Join(dir, "options.json"),
+					OutputPath:      filepath.Join(dir, "build"),
+				},
+				Name:     "ethereum",
+				Language: "golang",
+				Modes:    []string{"contracts"},
+			}},
+		},
+		{
+			name:    "json deployment list",
+			file:    "solgen.json",
+			content: `{"targets": [{"deployment": ["a.json", "-", "https://example.com/b.json", "/abs/c.json"]}]}`,
+			targets: []Target{{
+				Config: Config{
+					DeploymentPaths: paths{filepath.Join(dir, "a.json"), "-", "https://example.com/b.json", "/abs/c.json"},
+				},
+				Language: "golang",
+			}},
+		},
+		{
+			name: "yaml",
+			file: "solgen.yaml",
+			content: "targets:\n" +
+				"  - name: klaytn\n" +
+				"    deployment: ./deployment.json\n" +
+				"    platform: klaytn\n" +
+				"    output: ../build\n" +
+				"    only: [Token]\n",
+			targets: []Target{{
+				Config: Config{
+					DeploymentPaths: paths{filepath.Join(dir, "deployment.json")},
+					OutputPath:      filepath.Join(filepath.Dir(dir), "build"),
+					Platform:        "klaytn",
+				},
+				Name:     "klaytn",
+				Language: "golang",
+				Only:     []string{"Token"},
+			}},
+		},
+		{
+			name: "yaml deployment list",
+			file: "solgen.yml",
+			content: "targets:\n" +
+				"  - deployment: [a.json, b.json]\n" +
+				"    http_headers: [\"X-Api-Key: key\"]\n",
+			targets: []Target{{
+				Config: Config{
+					DeploymentPaths: paths{filepath.Join(dir, "a.json"), filepath.Join(dir, "b.json")},
+					HTTPHeaders:     []string{"X-Api-Key: key"},
+				},
+				Language: "golang",
+			}},
+		},
+		{
+			name:    "yaml unknown field",
+			file:    "solgen.yaml",
+			content: "targets:\n  - deployment: a.json\n    outputs: build\n",
+			err:     "field outputs not found",
+		},
+		{
+			name:    "invalid json",
+			file:    "solgen.json",
+			content: `{"targets": {}}`,
+			err:     "parse project",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(dir, tc.file)
+			require.NoError(t, ioutil.WriteFile(path, []byte(tc.content), 0644))
+
+			project, err := loadProject(path)
+			if tc.err != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.targets, project.Targets)
+		})
+	}
+}
+
+func TestProjectTargetsOverrides(t *testing.T) {
+	defer filet.CleanUp(t)
+	dir := filet.TmpDir(t, "")
+	path := filepath.Join(dir, "solgen.yaml")
+	filet.File(t, path, "targets:\n"+
+		"  - deployment: deployment.json\n"+
+		"    output: build\n"+
+		"    platform: ethereum\n"+
+		"    network: ropsten\n")
+
+	defer func(config Config) { overrides = config }(overrides)
+	for _, tc := range []struct {
+		name     string
+		env      Config
+		flags    Config
+		expected Config
+	}{
+		{
+			name: "target",
+			expected: Config{
+				DeploymentPaths: paths{filepath.Join(dir, "deployment.json")},
+				OutputPath:      filepath.Join(dir, "build"),
+				Platform:        "ethereum",
+				Network:         "ropsten",
+			},
+		},
+		{
+			name: "env over target",
+			env:  Config{OutputPath: "env", Network: "baobab", HTTPAttempts: 5},
+			expected: Config{
+				DeploymentPaths: paths{filepath.Join(dir, "deployment.json")},
+				OutputPath:      "env",
+				Platform:        "ethereum",
+				Network:         "baobab",
+				HTTPAttempts:    5,
+			},
+		},
+		{
+			name:  "flags over env",
+			env:   Config{OutputPath: "env", Network: "baobab", HTTPAttempts: 5},
+			flags: Config{DeploymentPaths: paths{"flag.json"}, OutputPath: "flag"},
+			expected: Config{
+				DeploymentPaths: paths{"flag.json"},
+				OutputPath:      "flag",
+				Platform:        "ethereum",
+				Network:         "baobab",
+				HTTPAttempts:    5,
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			overrides = tc.env.Merge(tc.flags)
+			targets, err := projectTargets(path)
+			require.NoError(t, err)
+			require.Len(t, targets, 1)
+
+			expected := tc.expected
+			expected.HTTPCache = targets[0].HTTPCache // user cache directory by default
+			if expected.HTTPAttempts == 0 {
+				expected.HTTPAttempts = 3
+			}
+			expected.HTTPTimeout = 30 * time.Second
+			assert.Equal(t, expected, targets[0].Config)
+			assert.Equal(t, "golang", targets[0].Language)
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/airbloc/solgen/bind"
+	"github.com/airbloc/solgen/proto"
+
+	"github.com/spf13/cobra"
+)
+
+var protoCmd = &cobra.Command{
+	Use:   "proto",
+	Short: "Generate protobuf service definitions",
+	Long: "Generates one .proto file per contract of the deployment.\n" +
+		"Struct names are taken from the structs section of the custom bind options.",
+	PreRunE: requireDeployment,
+	RunE:    func(cmd *cobra.Command, args []string) error { return runProto() },
+}
+
+// protoOptions converts custom bind options into proto type options.
+// Struct names are stripped from their Go package and slice notations
+// because they are used as message names as-is.
+func protoOptions(customs map[string]bind.Customs) proto.Options {
+	opts := make(proto.Options)
+	for name, custom := range customs {
+		opt := make(map[string]string)
+		for exp, strt := range custom.Structs {
+			strt = strings.TrimLeft(strt, "[]*")
+			opt[exp] = strt[strings.LastIndex(strt, ".")+1:]
+		}
+		opts[name] = opt
+	}
+	return opts
+}
+
+func runProto() error {
+	deployments, err := config.LoadDeployments()
+	if err != nil {
+		return err
+	}
+
+	opts, err := loadOptions(config.OptionPath)
+	if err != nil {
+		return err
+	}
+
+	deployments, _, err = filterDeployments(deployments, cmdFilter, opts.filter)
+	if err != nil {
+		return err
+	}
+
+	return proto.GenerateBind(config.OutputPath, deployments, protoOptions(opts.Customs))
+}
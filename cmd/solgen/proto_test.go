@@ -0,0 +1,101 @@
+package main
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/airbloc/solgen/bind"
+	"github.com/airbloc/solgen/proto"
+
+	"github.com/Flaque/filet"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const protoDeployment = `{
+  "Token": {
+    "address": "0x0000000000000000000000000000000000000001",
+    "abi": [{"type": "function", "name": "balanceOf", "stateMutability": "view",
+      "inputs": [{"name": "owner", "type": "address"}], "outputs": [{"name": "", "type": "uint256"}]}]
+  },
+  "Exchange": {
+    "address": "0x0000000000000000000000000000000000000002",
+    "abi": [{"type": "function", "name": "offer", "stateMutability": "view",
+      "inputs": [{"name": "id", "type": "bytes8"}],
+      "outputs": [{"name": "", "type": "tuple", "components": [{"name": "price", "type": "uint256"}, {"name": "seller", "type": "address"}]}]}]
+  },
+  "Vault": {
+    "address": "0x0000000000000000000000000000000000000003",
+    "abi": [{"type": "function", "name": "deposit", "stateMutability": "payable",
+      "inputs": [{"name": "amount", "type": "uint256"}], "outputs": []}]
+  },
+  "Migrations": {
+    "address": "0x0000000000000000000000000000000000000004",
+    "abi": [{"type": "function", "name": "owner", "stateMutability": "view",
+      "inputs": [], "outputs": [{"name": "", "type": "address"}]}]
+  }
+}`
+
+func TestProtoOptions(t *testing.T) {
+	opts := protoOptions(map[string]bind.Customs{
+		"Exchange": {Structs: map[string]string{
+			"(uint256,address)":   "types.Offer",
+			"(uint256,address)[]": "[]*types.Offer",
+			"(bytes8,uint256)":    "Order",
+		}},
+		"Token": {},
+	})
+	assert.Equal(t, proto.Options{
+		"Exchange": {
+			"(uint256,address)":   "Offer",
+			"(uint256,address)[]": "Offer",
+			"(bytes8,uint256)":    "Order",
+		},
+		"Token": {},
+	}, opts)
+}
+
+func TestRunProto(t *testing.T) {
+	defer filet.CleanUp(t)
+	dir := filet.TmpDir(t, "")
+	deploymentPath := filepath.Join(dir, "deployment.json")
+	filet.File(t, deploymentPath, protoDeployment)
+	optionPath := filepath.Join(dir, "options.json")
+	filet.File(t, optionPath, `{
+  "exclude": ["Migrations"],
+  "Exchange": {"structs": {"(uint256,address)": "*types.Offer"}}
+}`)
+	out := filepath.Join(dir, "proto")
+
+	defer func(c Config, f filter) { config, cmdFilter = c, f }(config, cmdFilter)
+	config = Config{DeploymentPaths: paths{deploymentPath}, OptionPath: optionPath, OutputPath: out}.WithDefaults()
+	cmdFilter = filter{Exclude: []string{"Vault"}}
+	require.NoError(t, runProto())
+
+	files, err := ioutil.ReadDir(out)
+	require.NoError(t, err)
+	var names []string
+	for _, file := range files {
+		names = append(names, file.Name())
+	}
+	assert.Equal(t, []string{"exchange.proto", "token.proto"}, names)
+
+	exchange, err := ioutil.ReadFile(filepath.Join(out, "exchange.proto"))
+	require.NoError(t, err)
+	assert.Contains(t, string(exchange), "message Offer {")
+	assert.NotContains(t, string(exchange), "types.")
+	assert.NotContains(t, string(exchange), proto.StructPrefix)
+
+	// --only narrows down the contracts left by the options file
+	cmdFilter = filter{Only: []string{"Token", "Vault"}}
+	config.OutputPath = filepath.Join(dir, "only")
+	require.NoError(t, runProto())
+	files, err = ioutil.ReadDir(config.OutputPath)
+	require.NoError(t, err)
+	names = nil
+	for _, file := range files {
+		names = append(names, file.Name())
+	}
+	assert.Equal(t, []string{"token.proto", "vault.proto"}, names)
+}
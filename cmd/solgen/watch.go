@@ -0,0 +1,226 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"time"
+
+	"github.com/airbloc/solgen/bind"
+	"github.com/airbloc/solgen/deployment"
+
+	"github.com/spf13/pflag"
+)
+
+var (
+	cmdWatch         bool
+	cmdWatchInterval time.Duration
+	cmdPollInterval  time.Duration
+)
+
+func addWatchFlags(flags *pflag.FlagSet) {
+	flags.BoolVar(&cmdWatch, "watch", false, "regenerate binds whenever the deployment or options file changes")
+	flags.DurationVar(&cmdWatchInterval, "watch-interval", time.Second, "interval of checking input files for changes in watch mode")
+	flags.DurationVar(&cmdPollInterval, "poll-interval", 10*time.Second, "interval of re-fetching deployment urls in watch mode")
+}
+
+// watcher regenerates the binds of a job whenever its inputs change.
+// Only contracts whose deployment or customs changed since the last generation are rewritten.
+type watcher struct {
+	*job
+	files        map[string]time.Time // Modification times of the local input files
+	lastPoll     time.Time            // Last time the deployment url has been fetched
+	fingerprints map[string]string    // Fingerprints of each contract inputs at the last generation
+}
+
+func newWatcher(target Target) (*watcher, error) {
+	j, err := newJob(target)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &watcher{
+		job:          j,
+		files:        make(map[string]time.Time),
+		lastPoll:     time.Now(),
+		fingerprints: make(map[string]string),
+	}
+	for _, path := range target.DeploymentPaths {
+		if !deployment.IsURL(path) {
+			w.files[path] = time.Time{}
+		}
+	}
+	if target.OptionPath != "" {
+		w.files[target.OptionPath] = time.Time{}
+	}
+	w.changed(w.lastPoll)
+	return w, nil
+}
+
+// fingerprint hashes every input of a contract affecting its generated binds.
+func fingerprint(contract deployment.Deployment, customs bind.Customs) (string, error) {
+	data, err := json.Marshal(struct {
+		Deployment deployment.Deployment
+		Customs    bind.Customs
+	}{contract, customs})
+	if err != nil {
+		return "", err
+	}
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:]), nil
+}
+
+// latestModTime returns the modification time of a file,
+// or the latest one of the files in a directory like build artifacts.
+func latestModTime(path string) (time.Time, error) {
+	var latest time.Time
+	err := filepath.Walk(path, func(_ string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+		return nil
+	})
+	return latest, err
+}
+
+// changed reports whether any input file has been modified or the deployment url
+// should be fetched again since the last call.
+func (w *watcher) changed(now time.Time) bool {
+	changed := false
+	for path, modTime := range w.files {
+		latest, err := latestModTime(path)
+		if err != nil {
+			continue
+		}
+		if !latest.Equal(modTime) {
+			w.files[path] = latest
+			changed = true
+		}
+	}
+	for _, path := range w.DeploymentPaths {
+		if deployment.IsURL(path) && now.Sub(w.lastPoll) >= cmdPollInterval {
+			w.lastPoll = now
+			changed = true
+		}
+	}
+	return changed
+}
+
+// regenerate reloads the inputs and rewrites the binds of every changed contract.
+func (w *watcher) regenerate() error {
+	deployments, filtered, customs, err := w.load()
+	if err != nil {
+		return err
+	}
+
+	changed := make(deployment.Deployments)
+	fingerprints := make(map[string]string)
+	for name, contract := range deployments {
+		fp, err := fingerprint(contract, customs[name])
+		if err != nil {
+			return err
+		}
+		fingerprints[name] = fp
+		if w.fingerprints[name] != fp {
+			changed[name] = contract
+		}
+	}
+	for name := range w.fingerprints {
+		if _, ok := fingerprints[name]; !ok {
+			log.Printf("%s: %s has been removed from the deployment", w.Target, name)
+		}
+	}
+	w.fingerprints = fingerprints
+
+	if len(changed) == 0 {
+		return nil
+	}
+	outputs, failures := w.generate(changed, customs)
+	writeFailures, err := w.write(outputs)
+	if err != nil {
+		return err
+	}
+	failures = append(failures, writeFailures...)
+
+	previous, err := loadLock(w.lockPath())
+	if err != nil {
+		return err
+	}
+	next, err := w.lock(previous, deployments, filtered, customs, outputs, failures)
+	if err != nil {
+		return err
+	}
+	reportABIChanges(os.Stderr, w.Target, previous, next)
+	if err := w.writeLock(next); err != nil {
+		return err
+	}
+
+	failed := make(map[string]bool)
+	for _, f := range failures {
+		failed[f.Contract+"/"+string(f.Mode)] = true
+		log.Printf("%s: %s", w.Target, f)
+	}
+	for _, out := range outputs {
+		if !failed[out.Contract+"/"+string(out.Mode)] {
+			log.Printf("%s: rewrote %s", w.Target, out.Path)
+		}
+	}
+	return nil
+}
+
+// watchTargets generates the targets, then keeps regenerating them on changes until interrupted.
+func watchTargets(targets ...Target) error {
+	if cmdCheck || cmdDryRun {
+		return errors.New("--watch can't be used with --check or --dry-run")
+	}
+	for _, target := range targets {
+		if target.DeploymentPaths.stdinReads() > 0 {
+			return errors.New("--watch can't read the deployment from stdin")
+		}
+	}
+
+	watchers := make([]*watcher, len(targets))
+	for i, target := range targets {
+		w, err := newWatcher(target)
+		if err != nil {
+			return err
+		}
+		if err := w.regenerate(); err != nil {
+			return err
+		}
+		watchers[i] = w
+	}
+	log.Println("watching for changes, press Ctrl+C to stop")
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	defer signal.Stop(interrupt)
+
+	ticker := time.NewTicker(cmdWatchInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-interrupt:
+			return nil
+		case now := <-ticker.C:
+			for _, w := range watchers {
+				if !w.changed(now) {
+					continue
+				}
+				// keep watching on failures, the next change may fix them
+				if err := w.regenerate(); err != nil {
+					log.Printf("%s: %v", w.Target, err)
+				}
+			}
+		}
+	}
+}
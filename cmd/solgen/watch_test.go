@@ -0,0 +1,113 @@
+package main
+
+import (
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/airbloc/solgen/bind"
+	"github.com/airbloc/solgen/deployment"
+
+	"github.com/Flaque/filet"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFingerprint(t *testing.T) {
+	contract := deployment.Deployment{
+		Address:   common.HexToAddress("0x01"),
+		CreatedAt: big.NewInt(1),
+		ParsedABI: []map[string]interface{}{{"type": "function", "name": "transfer"}},
+	}
+	customs := bind.Customs{Deny: []string{"kill"}}
+	expected, err := fingerprint(contract, customs)
+	require.NoError(t, err)
+
+	moved := contract
+	moved.Address = common.HexToAddress("0x02")
+	changedABI := contract
+	changedABI.ParsedABI = []map[string]interface{}{{"type": "function", "name": "approve"}}
+
+	for _, tc := range []struct {
+		name     string
+		contract deployment.Deployment
+		customs  bind.Customs
+		changed  bool
+	}{
+		{name: "same inputs", contract: contract, customs: bind.Customs{Deny: []string{"kill"}}},
+		{name: "address", contract: moved, customs: customs, changed: true},
+		{name: "abi", contract: changedABI, customs: customs, changed: true},
+		{name: "customs", contract: contract, customs: bind.Customs{Deny: []string{"pause"}}, changed: true},
+		{name: "type map", contract: contract, customs: bind.Customs{
+			Deny:    []string{"kill"},
+			TypeMap: bind.TypeMap{"bytes8": {Type: "[8]byte"}},
+		}, changed: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			fp, err := fingerprint(tc.contract, tc.customs)
+			require.NoError(t, err)
+			assert.Equal(t, tc.changed, fp != expected)
+		})
+	}
+}
+
+func TestLatestModTime(t *testing.T) {
+	defer filet.CleanUp(t)
+	dir := filet.TmpDir(t, "")
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "contracts"), os.ModePerm))
+	token := filepath.Join(dir, "contracts", "Token.json")
+	exchange := filepath.Join(dir, "Exchange.json")
+	filet.File(t, token, "{}")
+	filet.File(t, exchange, "{}")
+
+	past := time.Now().Add(-time.Hour).Truncate(time.Second)
+	for _, path := range []string{dir, filepath.Join(dir, "contracts"), exchange} {
+		require.NoError(t, os.Chtimes(path, past, past))
+	}
+	latest := past.Add(time.Minute)
+	require.NoError(t, os.Chtimes(token, latest, latest))
+
+	modTime, err := latestModTime(dir)
+	require.NoError(t, err)
+	assert.True(t, latest.Equal(modTime), "latest of directory")
+
+	modTime, err = latestModTime(exchange)
+	require.NoError(t, err)
+	assert.True(t, past.Equal(modTime), "file")
+
+	_, err = latestModTime(filepath.Join(dir, "missing.json"))
+	assert.Error(t, err)
+}
+
+func TestWatcherChanged(t *testing.T) {
+	defer filet.CleanUp(t)
+	dir := filet.TmpDir(t, "")
+	path := filepath.Join(dir, "deployment.json")
+	filet.File(t, path, "{}")
+
+	defer func(interval time.Duration) { cmdPollInterval = interval }(cmdPollInterval)
+	cmdPollInterval = time.Minute
+
+	start := time.Now()
+	w := &watcher{
+		job:      &job{Target: Target{Config: Config{DeploymentPaths: paths{path, "https://example.com/deployment.json"}}}},
+		files:    map[string]time.Time{path: {}},
+		lastPoll: start,
+	}
+	assert.True(t, w.changed(start), "first check")
+	assert.False(t, w.changed(start.Add(time.Second)), "nothing changed")
+
+	modified := time.Now().Add(time.Hour)
+	require.NoError(t, os.Chtimes(path, modified, modified))
+	assert.True(t, w.changed(start.Add(2*time.Second)), "file modified")
+	assert.False(t, w.changed(start.Add(3*time.Second)), "file unchanged since")
+
+	assert.True(t, w.changed(start.Add(time.Minute)), "url poll")
+	assert.False(t, w.changed(start.Add(time.Minute+time.Second)), "url polled recently")
+
+	require.NoError(t, os.Remove(path))
+	assert.False(t, w.changed(start.Add(time.Minute+2*time.Second)), "missing file")
+}
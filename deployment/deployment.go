@@ -4,9 +4,10 @@ import (
 	"bytes"
 	"encoding/json"
 	"io"
+	"io/ioutil"
 	"math/big"
-	"net/http"
 	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/ethereum/go-ethereum/accounts/abi"
@@ -19,55 +20,158 @@ type Deployment struct {
 	TxHash    common.Hash              `json:"tx_hash"`
 	CreatedAt *big.Int                 `json:"created_at"`
 	ParsedABI []map[string]interface{} `json:"abi"`
+	Bytecode  string                   `json:"bytecode,omitempty"` // Creation bytecode in hex, may contain unlinked library placeholders
+	DevDoc    json.RawMessage          `json:"devdoc,omitempty"`   // Developer NatSpec documentation
+	UserDoc   json.RawMessage          `json:"userdoc,omitempty"`  // User NatSpec documentation
+	Networks  map[string]Network       `json:"networks,omitempty"` // Deployments by chain ID, for contracts deployed to several chains; only read from deployment files
 	EvmABI    abi.ABI                  `json:"-"`
 	RawABI    []byte                   `json:"-"`
 }
 
+// Network is the deployment of a contract to a single chain.
+type Network struct {
+	Address   common.Address `json:"address"`
+	TxHash    common.Hash    `json:"tx_hash"`
+	CreatedAt *big.Int       `json:"created_at"`
+}
+
 type Deployments map[string]Deployment
 
-func fromUrl(path string) (io.ReadCloser, error) {
-	resp, err := http.Get(path)
-	if err != nil {
-		return nil, err
-	}
-	return resp.Body, err
+// Loader loads deployments from deployment files, urls and build artifact directories.
+type Loader struct {
+	Network string   // Network ID (or hardhat-deploy network name) picked from multi-network artifacts, required if there are several
+	Fetcher *Fetcher // Fetcher of deployment urls, the zero Fetcher if nil
+
+	AllowOverride bool // Whether LoadAll lets later paths redefine contracts differently
+}
+
+// StdinPath is the deployment path reading the deployment file from the standard input.
+const StdinPath = "-"
+
+// IsURL reports whether the deployment path is fetched over HTTP.
+func IsURL(path string) bool {
+	return strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://")
 }
 
 func fromFile(path string) (io.ReadCloser, error) {
 	return os.OpenFile(path, os.O_RDONLY, os.ModePerm)
 }
 
+// GetDeploymentsFrom loads deployments from path with the default loader.
 func GetDeploymentsFrom(path string) (Deployments, error) {
-	reader, err := func(path string) (io.ReadCloser, error) {
-		if strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://") {
-			return fromUrl(path)
+	return new(Loader).Load(path)
+}
+
+// GetDeploymentsFromReader reads deployments from reader with the default loader.
+func GetDeploymentsFromReader(reader io.Reader) (Deployments, error) {
+	return new(Loader).LoadReader(reader)
+}
+
+// Load reads deployments from a deployment file or url, the standard input if path is StdinPath,
+// or a directory of build artifacts.
+// The file or url may also hold the output of solc, from --combined-json or --standard-json.
+func (l *Loader) Load(path string) (Deployments, error) {
+	if IsURL(path) {
+		fetcher := l.Fetcher
+		if fetcher == nil {
+			fetcher = new(Fetcher)
 		}
-		return fromFile(path)
-	}(path)
+		data, err := fetcher.Fetch(path)
+		if err != nil {
+			return nil, err
+		}
+		return decodeDeployments(data)
+	}
+
+	if path == StdinPath {
+		return l.LoadReader(os.Stdin)
+	}
+
+	if stat, err := os.Stat(path); err == nil && stat.IsDir() {
+		return l.fromDirectory(path)
+	}
+
+	reader, err := fromFile(path)
 	if err != nil {
 		return nil, err
 	}
 	defer reader.Close()
+	return l.LoadReader(reader)
+}
 
-	deployments := make(Deployments)
-	if err := json.NewDecoder(reader).Decode(&deployments); err != nil {
+// LoadReader reads deployments from the content of a deployment file or solc output.
+func (l *Loader) LoadReader(reader io.Reader) (Deployments, error) {
+	data, err := ioutil.ReadAll(reader)
+	if err != nil {
 		return nil, err
 	}
+	return decodeDeployments(data)
+}
+
+// fromDirectory detects the layout of build artifacts in the directory and reads them.
+// Hardhat, hardhat-deploy and Foundry layouts are detected, anything else is read as Truffle artifacts.
+func (l *Loader) fromDirectory(dir string) (Deployments, error) {
+	switch {
+	case isHardhatDeployments(dir):
+		return l.fromHardhatDeployments(dir)
+	case isFoundryProject(dir):
+		return l.fromFoundryProject(dir)
+	case isFoundryArtifacts(dir):
+		return l.fromFoundryArtifacts(dir)
+	case isHardhatArtifacts(dir):
+		return l.fromHardhatArtifacts(dir)
+	default:
+		return l.fromTruffle(dir)
+	}
+}
 
+// walkArtifacts calls fn with every contract artifact file in <Source>.sol directories,
+// the layout shared by Hardhat and Foundry. Debug files and build infos are skipped.
+func walkArtifacts(dir string, fn func(path string) error) error {
+	return filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if info.Name() == "build-info" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if filepath.Ext(path) != ".json" || strings.HasSuffix(path, ".dbg.json") ||
+			filepath.Ext(filepath.Dir(path)) != ".sol" {
+			return nil
+		}
+		return fn(path)
+	})
+}
+
+// parseABIs fills the raw and evm ABIs of every deployment from its parsed one.
+// ABIs of solc >= 0.6 lack the constant field, so it is derived from the state mutability.
+func (deployments Deployments) parseABIs() error {
 	for contractName, deployment := range deployments {
+		for _, entry := range deployment.ParsedABI {
+			if _, ok := entry["constant"]; ok {
+				continue
+			}
+			if mutability, ok := entry["stateMutability"]; ok && entry["type"] != "constructor" {
+				entry["constant"] = mutability == "view" || mutability == "pure"
+			}
+		}
+
 		rawABI, err := json.Marshal(deployment.ParsedABI)
 		if err != nil {
-			return nil, errors.Wrap(err, "parse to raw abi")
+			return errors.Wrapf(err, "%s: parse to raw abi", contractName)
 		}
 
 		evmABI, err := abi.JSON(bytes.NewReader(rawABI))
 		if err != nil {
-			return nil, errors.Wrap(err, "parse to evm abi")
+			return errors.Wrapf(err, "%s: parse to evm abi", contractName)
 		}
 
 		deployment.RawABI = rawABI
 		deployment.EvmABI = evmABI
 		deployments[contractName] = deployment
 	}
-	return deployments, nil
+	return nil
 }
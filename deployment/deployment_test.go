@@ -6,6 +6,7 @@ import (
 	"net/http/httptest"
 	"net/url"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/Flaque/filet"
@@ -71,7 +72,7 @@ func TestGetDeploymentsFromUrlInvalidDeployment(t *testing.T) {
 	)
 
 	_, err := GetDeploymentsFrom(testServer.URL)
-	assert.IsType(t, &json.UnmarshalTypeError{}, err)
+	assert.IsType(t, &ValidationError{}, err)
 }
 
 func TestGetDeploymentsFromFile(t *testing.T) {
@@ -103,5 +104,28 @@ func TestGetDeploymentsFromFileInvalidDeployment(t *testing.T) {
 	filet.File(t, TestDeploymentPath, TestDeploymentError)
 
 	_, err := GetDeploymentsFrom(TestDeploymentPath)
-	assert.IsType(t, &json.UnmarshalTypeError{}, err)
+	assert.IsType(t, &ValidationError{}, err)
+}
+
+const TestMultiNetworkDeployment = `{"Migrations":{"networks":{"1001":{"address":"0x5555555555555555555555555555555555555555","tx_hash":"0x6666666666666666666666666666666666666666666666666666666666666666","created_at":9},"8217":{"address":"0x3333333333333333333333333333333333333333","tx_hash":"0x4444444444444444444444444444444444444444444444444444444444444444","created_at":7}},"abi":[]}}`
+
+func TestGetDeploymentsFromFileMultiNetwork(t *testing.T) {
+	defer filet.CleanUp(t)
+	filet.File(t, TestDeploymentPath, TestMultiNetworkDeployment)
+
+	deployments, err := GetDeploymentsFrom(TestDeploymentPath)
+	assert.NoError(t, err)
+
+	networks := deployments["Migrations"].Networks
+	assert.Len(t, networks, 2)
+	assert.Equal(t, "0x3333333333333333333333333333333333333333", networks["8217"].Address.Hex())
+	assert.Equal(t, int64(9), networks["1001"].CreatedAt.Int64())
+}
+
+func TestGetDeploymentsFromReader(t *testing.T) {
+	deployments, err := GetDeploymentsFromReader(strings.NewReader(TestDeployment))
+	assert.NoError(t, err)
+
+	_, ok := deployments["Migrations"]
+	assert.True(t, ok)
 }
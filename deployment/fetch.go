@@ -0,0 +1,209 @@
+package deployment
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	DefaultTimeout  = 30 * time.Second
+	DefaultAttempts = 3
+	DefaultBackoff  = 500 * time.Millisecond
+)
+
+// Fetcher fetches deployments over HTTP.
+// The zero value fetches once with the default timeout and without cache.
+type Fetcher struct {
+	Header   http.Header   // Headers sent with every request, such as Authorization
+	Timeout  time.Duration // Timeout of each attempt, DefaultTimeout if zero
+	Attempts int           // Attempts before giving up, retrying network timeouts and server errors; one if zero
+	Backoff  time.Duration // Delay before the first retry, doubled after each one; DefaultBackoff if zero
+	CacheDir string        // Directory caching the fetched bodies by url, revalidated with their ETag; disabled if empty
+	ReadOnly bool          // Serves bodies cached in CacheDir without storing fetched ones, as in dry runs
+
+	// Logf reports a cached body served in place of a failed fetch, and failures of caching, if set.
+	Logf func(format string, v ...interface{})
+}
+
+// statusError is a response with a status code other than 2xx.
+type statusError struct {
+	url    string
+	status string
+	code   int
+}
+
+func (e *statusError) Error() string {
+	return "fetch " + e.url + ": " + e.status
+}
+
+// temporary reports whether a request may succeed if retried: on network timeouts
+// and temporary errors, and on server errors, timeouts and rate limits.
+func temporary(err error) bool {
+	switch err := errors.Cause(err).(type) {
+	case *statusError:
+		return err.code >= 500 || err.code == http.StatusTooManyRequests || err.code == http.StatusRequestTimeout
+	case net.Error:
+		return err.Timeout() || err.Temporary()
+	}
+	return false
+}
+
+// unreachable reports whether a request failed because the server can't be reached
+// or is failing, so that a cached body may be served in place. Malformed urls and
+// unsupported schemes are not unreachable.
+func unreachable(err error) bool {
+	if urlErr, ok := errors.Cause(err).(*url.Error); ok {
+		_, isNet := urlErr.Err.(net.Error)
+		return isNet
+	}
+	return temporary(err)
+}
+
+// Fetch returns the body of the url.
+// If the url can't be reached, the cached body is returned instead, if any.
+func (f *Fetcher) Fetch(url string) ([]byte, error) {
+	cached, etag := f.cached(url)
+
+	body, err := f.fetch(url, etag)
+	switch {
+	case err != nil && cached != nil && unreachable(err):
+		f.logf("%v; using the cached deployment", err)
+		return cached, nil
+	case err != nil:
+		return nil, err
+	case body == nil:
+		// Not modified since cached
+		return cached, nil
+	}
+	return body, nil
+}
+
+func (f *Fetcher) logf(format string, v ...interface{}) {
+	if f.Logf != nil {
+		f.Logf(format, v...)
+	}
+}
+
+// fetch requests the url until it succeeds or attempts run out.
+// The body is nil if the cached version with the given ETag is not modified.
+func (f *Fetcher) fetch(url, etag string) (body []byte, err error) {
+	attempts := f.Attempts
+	if attempts <= 0 {
+		attempts = 1
+	}
+	backoff := f.Backoff
+	if backoff <= 0 {
+		backoff = DefaultBackoff
+	}
+
+	for attempt := 1; ; attempt++ {
+		body, err = f.request(url, etag)
+		if err == nil || attempt >= attempts || !temporary(err) {
+			return body, err
+		}
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+func (f *Fetcher) request(url, etag string) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	for name, values := range f.Header {
+		req.Header[name] = values
+	}
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+
+	timeout := f.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	client := &http.Client{Timeout: timeout}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified && etag != "" {
+		return nil, nil
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, &statusError{url: url, status: resp.Status, code: resp.StatusCode}
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrapf(err, "fetch %s", url)
+	}
+	// The body is fetched anyway, only offline runs miss it
+	if err := f.store(url, body, resp.Header.Get("ETag")); err != nil {
+		f.logf("cache deployment %s: %v", url, err)
+	}
+	return body, nil
+}
+
+// cachePath returns the paths of the cached body of the url and its ETag.
+func (f *Fetcher) cachePath(url string) (body, etag string) {
+	sum := sha256.Sum256([]byte(url))
+	name := filepath.Join(f.CacheDir, hex.EncodeToString(sum[:]))
+	return name + ".json", name + ".etag"
+}
+
+// cached returns the cached body of the url and its ETag, nil if there is none.
+func (f *Fetcher) cached(url string) ([]byte, string) {
+	if f.CacheDir == "" {
+		return nil, ""
+	}
+	bodyPath, etagPath := f.cachePath(url)
+	body, err := ioutil.ReadFile(bodyPath)
+	if err != nil {
+		return nil, ""
+	}
+	etag, _ := ioutil.ReadFile(etagPath)
+	return body, string(etag)
+}
+
+// store caches the body of a url. Bodies may be fetched with credentials, so the cache is
+// readable by its owner only; files cached with wider permissions by earlier versions are narrowed.
+func (f *Fetcher) store(url string, body []byte, etag string) error {
+	if f.CacheDir == "" || f.ReadOnly {
+		return nil
+	}
+	if err := os.MkdirAll(f.CacheDir, 0700); err != nil {
+		return err
+	}
+	bodyPath, etagPath := f.cachePath(url)
+	if err := writePrivateFile(bodyPath, body); err != nil {
+		return err
+	}
+	if etag == "" {
+		if err := os.Remove(etagPath); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	return writePrivateFile(etagPath, []byte(etag))
+}
+
+// writePrivateFile writes a file readable and writable by its owner only, even if it already existed.
+func writePrivateFile(path string, data []byte) error {
+	if err := ioutil.WriteFile(path, data, 0600); err != nil {
+		return err
+	}
+	return os.Chmod(path, 0600)
+}
@@ -0,0 +1,230 @@
+package deployment
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFetcherStatusError(t *testing.T) {
+	testServer := httptest.NewServer(http.NotFoundHandler())
+	defer testServer.Close()
+
+	_, err := GetDeploymentsFrom(testServer.URL + "/deployment.json")
+	assert.EqualError(t, err, "fetch "+testServer.URL+"/deployment.json: 404 Not Found")
+}
+
+func TestFetcherHeaderAndRetries(t *testing.T) {
+	requests := 0
+	testServer := httptest.NewServer(
+		http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			requests++
+			if requests < 3 {
+				http.Error(writer, "unavailable", http.StatusServiceUnavailable)
+				return
+			}
+			assert.Equal(t, "Bearer secret", request.Header.Get("Authorization"))
+			writer.Write([]byte(TestDeployment))
+		}),
+	)
+	defer testServer.Close()
+
+	fetcher := &Fetcher{
+		Header:   http.Header{"Authorization": {"Bearer secret"}},
+		Attempts: 3,
+		Backoff:  time.Millisecond,
+	}
+	deployments, err := (&Loader{Fetcher: fetcher}).Load(testServer.URL)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, requests)
+
+	_, ok := deployments["Migrations"]
+	assert.True(t, ok)
+}
+
+func TestFetcherCache(t *testing.T) {
+	cacheDir, err := ioutil.TempDir("", "solgen-cache")
+	assert.NoError(t, err)
+	defer os.RemoveAll(cacheDir)
+
+	modified := 0
+	testServer := httptest.NewServer(
+		http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			if request.Header.Get("If-None-Match") == `"v1"` {
+				writer.WriteHeader(http.StatusNotModified)
+				return
+			}
+			modified++
+			writer.Header().Set("ETag", `"v1"`)
+			writer.Write([]byte(TestDeployment))
+		}),
+	)
+
+	fetcher := &Fetcher{CacheDir: cacheDir}
+	for i := 0; i < 2; i++ {
+		body, err := fetcher.Fetch(testServer.URL)
+		assert.NoError(t, err)
+		assert.Equal(t, TestDeployment, string(body))
+	}
+	assert.Equal(t, 1, modified)
+
+	// Served from the cache while offline
+	testServer.Close()
+	body, err := fetcher.Fetch(testServer.URL)
+	assert.NoError(t, err)
+	assert.Equal(t, TestDeployment, string(body))
+}
+
+func TestFetcherCachePermissions(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("file modes are not enforced on windows")
+	}
+	cacheDir, err := ioutil.TempDir("", "solgen-cache")
+	assert.NoError(t, err)
+	defer os.RemoveAll(cacheDir)
+
+	testServer := httptest.NewServer(
+		http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			writer.Header().Set("ETag", `"v1"`)
+			writer.Write([]byte(TestDeployment))
+		}),
+	)
+	defer testServer.Close()
+
+	fetcher := &Fetcher{Header: http.Header{"Authorization": {"Bearer secret"}}, CacheDir: filepath.Join(cacheDir, "solgen")}
+	bodyPath, etagPath := fetcher.cachePath(testServer.URL)
+	// Cached by an earlier version with wider permissions
+	assert.NoError(t, os.MkdirAll(fetcher.CacheDir, 0700))
+	assert.NoError(t, ioutil.WriteFile(bodyPath, []byte("{}"), 0644))
+
+	_, err = fetcher.Fetch(testServer.URL)
+	assert.NoError(t, err)
+	for path, mode := range map[string]os.FileMode{fetcher.CacheDir: 0700, bodyPath: 0600, etagPath: 0600} {
+		info, err := os.Stat(path)
+		if assert.NoError(t, err) {
+			assert.Equal(t, mode, info.Mode().Perm(), path)
+		}
+	}
+
+	// A new cache directory is private too
+	os.RemoveAll(fetcher.CacheDir)
+	_, err = fetcher.Fetch(testServer.URL)
+	assert.NoError(t, err)
+	info, err := os.Stat(fetcher.CacheDir)
+	if assert.NoError(t, err) {
+		assert.Equal(t, os.FileMode(0700), info.Mode().Perm())
+	}
+}
+
+func TestFetcherReadOnlyCache(t *testing.T) {
+	cacheDir, err := ioutil.TempDir("", "solgen-cache")
+	assert.NoError(t, err)
+	defer os.RemoveAll(cacheDir)
+
+	testServer := httptest.NewServer(
+		http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			writer.Header().Set("ETag", `"v1"`)
+			writer.Write([]byte(TestDeployment))
+		}),
+	)
+	defer testServer.Close()
+
+	fetcher := &Fetcher{CacheDir: cacheDir, ReadOnly: true}
+	body, err := fetcher.Fetch(testServer.URL)
+	assert.NoError(t, err)
+	assert.Equal(t, TestDeployment, string(body))
+
+	files, err := ioutil.ReadDir(cacheDir)
+	assert.NoError(t, err)
+	assert.Empty(t, files)
+
+	// Bodies cached by earlier runs are still served
+	fetcher.ReadOnly = false
+	_, err = fetcher.Fetch(testServer.URL)
+	assert.NoError(t, err)
+	testServer.Close()
+
+	fetcher.ReadOnly = true
+	body, err = fetcher.Fetch(testServer.URL)
+	assert.NoError(t, err)
+	assert.Equal(t, TestDeployment, string(body))
+}
+
+// timeoutError is a network error, timed out or temporary as configured.
+type timeoutError struct{ timeout, temporary bool }
+
+func (e timeoutError) Error() string   { return "i/o timeout" }
+func (e timeoutError) Timeout() bool   { return e.timeout }
+func (e timeoutError) Temporary() bool { return e.temporary }
+
+func TestFetcherTemporaryErrors(t *testing.T) {
+	refused := &url.Error{Op: "Get", URL: "http://localhost:1", Err: &net.OpError{Op: "dial", Err: syscall.ECONNREFUSED}}
+	for _, tc := range []struct {
+		name        string
+		err         error
+		temporary   bool
+		unreachable bool
+	}{
+		{name: "server error", err: &statusError{code: http.StatusBadGateway}, temporary: true, unreachable: true},
+		{name: "rate limited", err: &statusError{code: http.StatusTooManyRequests}, temporary: true, unreachable: true},
+		{name: "request timeout", err: &statusError{code: http.StatusRequestTimeout}, temporary: true, unreachable: true},
+		{name: "not found", err: &statusError{code: http.StatusNotFound}},
+		{name: "timeout", err: &url.Error{Op: "Get", Err: timeoutError{timeout: true}}, temporary: true, unreachable: true},
+		{name: "temporary", err: timeoutError{temporary: true}, temporary: true, unreachable: true},
+		{name: "wrapped body read", err: errors.Wrap(timeoutError{timeout: true}, "fetch"), temporary: true, unreachable: true},
+		{name: "connection refused", err: refused, unreachable: true},
+		{name: "malformed url", err: &url.Error{Op: "parse", URL: "http://[::1", Err: errors.New("missing ']' in host")}},
+		{name: "unsupported scheme", err: &url.Error{Op: "Get", URL: "ftp://x", Err: errors.New("unsupported protocol scheme \"ftp\"")}},
+		{name: "other", err: errors.New("cache deployment: permission denied")},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.temporary, temporary(tc.err), "temporary")
+			assert.Equal(t, tc.unreachable, unreachable(tc.err), "unreachable")
+		})
+	}
+}
+
+func TestFetcherMalformedURL(t *testing.T) {
+	// Not retried, or the backoff would hang the test
+	fetcher := &Fetcher{Attempts: 3, Backoff: time.Hour}
+	_, err := fetcher.Fetch("http://[::1")
+	assert.Error(t, err)
+}
+
+func TestFetcherCacheStoreFailure(t *testing.T) {
+	cacheFile, err := ioutil.TempFile("", "solgen-cache")
+	assert.NoError(t, err)
+	cacheFile.Close()
+	defer os.Remove(cacheFile.Name())
+
+	testServer := httptest.NewServer(
+		http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			writer.Write([]byte(TestDeployment))
+		}),
+	)
+	defer testServer.Close()
+
+	var logs []string
+	fetcher := &Fetcher{
+		CacheDir: filepath.Join(cacheFile.Name(), "cache"), // under a file, so that storing fails
+		Logf:     func(format string, v ...interface{}) { logs = append(logs, fmt.Sprintf(format, v...)) },
+	}
+	body, err := fetcher.Fetch(testServer.URL)
+	assert.NoError(t, err)
+	assert.Equal(t, TestDeployment, string(body))
+	if assert.Len(t, logs, 1) {
+		assert.Contains(t, logs[0], "cache deployment "+testServer.URL)
+	}
+}
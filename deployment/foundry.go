@@ -0,0 +1,262 @@
+package deployment
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+)
+
+const (
+	foundryConfigFile    = "foundry.toml"
+	foundryArtifactsDir  = "out"
+	foundryBroadcastDir  = "broadcast"
+	foundryBroadcastFile = "run-latest.json"
+)
+
+// forgeStdSources are the sources of forge-std, compiled along the tests and scripts of a project.
+var forgeStdSources = map[string]bool{
+	"Base.sol": true, "IMulticall3.sol": true, "Script.sol": true, "Test.sol": true, "Vm.sol": true,
+	"console.sol": true, "console2.sol": true, "safeconsole.sol": true,
+	"StdAssertions.sol": true, "StdChains.sol": true, "StdCheats.sol": true, "StdError.sol": true,
+	"StdInvariant.sol": true, "StdJson.sol": true, "StdMath.sol": true, "StdStorage.sol": true,
+	"StdStyle.sol": true, "StdToml.sol": true, "StdUtils.sol": true,
+}
+
+// isFoundryProjectArtifact reports whether an artifact is of a contract of the project,
+// rather than of a test, a script or forge-std.
+func isFoundryProjectArtifact(path string) bool {
+	source := filepath.Base(filepath.Dir(path))
+	return !strings.HasSuffix(source, ".t.sol") && !strings.HasSuffix(source, ".s.sol") && !forgeStdSources[source]
+}
+
+// foundryArtifact is a contract artifact of Foundry, found in out/<Source>.sol/<Contract>.json.
+type foundryArtifact struct {
+	ABI      []map[string]interface{} `json:"abi"`
+	Bytecode struct {
+		Object string `json:"object"`
+	} `json:"bytecode"`
+}
+
+// foundryBroadcast is a script run of Foundry, found in broadcast/<Script>.s.sol/<chainId>/run-latest.json.
+type foundryBroadcast struct {
+	Transactions []struct {
+		Hash            common.Hash    `json:"hash"`
+		TransactionType string         `json:"transactionType"`
+		ContractName    string         `json:"contractName"`
+		ContractAddress common.Address `json:"contractAddress"`
+	} `json:"transactions"`
+	Receipts []struct {
+		TransactionHash common.Hash `json:"transactionHash"`
+		BlockNumber     blockNumber `json:"blockNumber"`
+	} `json:"receipts"`
+	Timestamp int64 `json:"timestamp"`
+}
+
+// blockNumber is a block number encoded either as a JSON number or a hex string.
+type blockNumber struct {
+	*big.Int
+}
+
+func (n *blockNumber) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		text = string(data)
+	}
+
+	value, ok := new(big.Int).SetString(text, 0)
+	if !ok {
+		return errors.Errorf("invalid block number %s", data)
+	}
+	n.Int = value
+	return nil
+}
+
+// foundryArtifactName returns the contract name of an artifact file, stripping
+// the compiler version suffix added when several versions are used (e.g. Token.0.8.19.json).
+func foundryArtifactName(path string) string {
+	name := filepath.Base(path)
+	return name[:strings.Index(name, ".")]
+}
+
+// isFoundryArtifacts reports whether the directory holds Foundry artifacts.
+func isFoundryArtifacts(dir string) bool {
+	err := walkArtifacts(dir, func(path string) error {
+		var artifact struct {
+			Bytecode map[string]interface{} `json:"bytecode"`
+		}
+		if data, err := ioutil.ReadFile(path); err == nil && json.Unmarshal(data, &artifact) == nil {
+			if _, ok := artifact.Bytecode["object"]; ok {
+				return errFound
+			}
+		}
+		return filepath.SkipDir
+	})
+	return err == errFound
+}
+
+// isFoundryProject reports whether the directory is the root of a Foundry project.
+func isFoundryProject(dir string) bool {
+	if _, err := os.Stat(filepath.Join(dir, foundryConfigFile)); err == nil {
+		return true
+	}
+	return isFoundryArtifacts(filepath.Join(dir, foundryArtifactsDir))
+}
+
+// fromFoundryArtifacts reads the artifacts of a Foundry out directory, skipping the ones
+// of tests, scripts and forge-std. Artifacts are not deployed, so their address and
+// transaction hash are left zero.
+func (l *Loader) fromFoundryArtifacts(dir string) (Deployments, error) {
+	deployments := make(Deployments)
+	sources := make(map[string]string)
+	err := walkArtifacts(dir, func(path string) error {
+		if !isFoundryProjectArtifact(path) {
+			return nil
+		}
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		var artifact foundryArtifact
+		if err := json.Unmarshal(data, &artifact); err != nil {
+			return errors.Wrapf(err, "parse foundry artifact %s", path)
+		}
+
+		// Artifacts of a contract compiled by several compiler versions share their source directory
+		name := foundryArtifactName(path)
+		source, err := filepath.Rel(dir, filepath.Dir(path))
+		if err != nil {
+			return err
+		}
+		if other, exists := sources[name]; exists && other != source {
+			return errors.Errorf("contract %s is defined in both %s and %s", name, other, source)
+		}
+		sources[name] = source
+
+		deployments[name] = Deployment{
+			CreatedAt: new(big.Int),
+			ParsedABI: artifact.ABI,
+			Bytecode:  artifact.Bytecode.Object,
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := deployments.parseABIs(); err != nil {
+		return nil, err
+	}
+	return deployments, nil
+}
+
+// foundryChain resolves the chain ID of the broadcasts. If no chain is given,
+// the scripts must be broadcast to a single chain. It returns an empty chain ID
+// if nothing is broadcast.
+func foundryChain(dir string, chain string) (string, error) {
+	runs, err := filepath.Glob(filepath.Join(dir, "*", "*", foundryBroadcastFile))
+	if err != nil {
+		return "", err
+	}
+
+	chains := make(map[string]bool)
+	for _, run := range runs {
+		chains[filepath.Base(filepath.Dir(run))] = true
+	}
+	if chain != "" {
+		if !chains[chain] {
+			return "", errors.Errorf("nothing is broadcast to chain %s", chain)
+		}
+		return chain, nil
+	}
+
+	ids := make([]string, 0, len(chains))
+	for id := range chains {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	switch len(ids) {
+	case 0:
+		return "", nil
+	case 1:
+		return ids[0], nil
+	default:
+		return "", errors.Errorf("foundry scripts are broadcast to several chains (%s), pick one of them", strings.Join(ids, ", "))
+	}
+}
+
+// readFoundryBroadcasts reads the latest runs of every script broadcast to the chain,
+// ordered by their timestamp.
+func readFoundryBroadcasts(dir string, chain string) ([]foundryBroadcast, error) {
+	runs, err := filepath.Glob(filepath.Join(dir, "*", chain, foundryBroadcastFile))
+	if err != nil {
+		return nil, err
+	}
+
+	broadcasts := make([]foundryBroadcast, len(runs))
+	for i, run := range runs {
+		data, err := ioutil.ReadFile(run)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(data, &broadcasts[i]); err != nil {
+			return nil, errors.Wrapf(err, "parse foundry broadcast %s", run)
+		}
+	}
+	sort.SliceStable(broadcasts, func(i, j int) bool { return broadcasts[i].Timestamp < broadcasts[j].Timestamp })
+	return broadcasts, nil
+}
+
+// fromFoundryProject reads the artifacts of a Foundry project, deployed by the scripts
+// broadcast to the chain picked by the network of the loader. Contracts which are not
+// deployed are skipped, unless nothing is broadcast at all.
+func (l *Loader) fromFoundryProject(dir string) (Deployments, error) {
+	artifacts, err := l.fromFoundryArtifacts(filepath.Join(dir, foundryArtifactsDir))
+	if err != nil {
+		return nil, err
+	}
+
+	broadcastDir := filepath.Join(dir, foundryBroadcastDir)
+	chain, err := foundryChain(broadcastDir, l.Network)
+	if err != nil || chain == "" {
+		return artifacts, err
+	}
+	broadcasts, err := readFoundryBroadcasts(broadcastDir, chain)
+	if err != nil {
+		return nil, err
+	}
+
+	deployments := make(Deployments)
+	for _, broadcast := range broadcasts {
+		blocks := make(map[common.Hash]*big.Int)
+		for _, receipt := range broadcast.Receipts {
+			blocks[receipt.TransactionHash] = receipt.BlockNumber.Int
+		}
+
+		for _, tx := range broadcast.Transactions {
+			// Contracts created without a known artifact, like by raw bytecode, have no name
+			if tx.TransactionType != "CREATE" && tx.TransactionType != "CREATE2" || tx.ContractName == "" {
+				continue
+			}
+			deployment, ok := artifacts[tx.ContractName]
+			if !ok {
+				return nil, errors.Errorf("artifact of broadcast contract %s not found", tx.ContractName)
+			}
+
+			deployment.Address = tx.ContractAddress
+			deployment.TxHash = tx.Hash
+			if block, ok := blocks[tx.Hash]; ok && block != nil {
+				deployment.CreatedAt = block
+			}
+			deployments[tx.ContractName] = deployment
+		}
+	}
+	return deployments, nil
+}
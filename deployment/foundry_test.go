@@ -0,0 +1,114 @@
+package deployment
+
+import (
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Flaque/filet"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+const TestFoundryArtifact = `{"abi":[{"type":"function","name":"number","inputs":[],"outputs":[{"name":"","type":"uint256","internalType":"uint256"}],"stateMutability":"view"}],"bytecode":{"object":"0x6080","sourceMap":"","linkReferences":{}},"deployedBytecode":{"object":"0x6080"},"methodIdentifiers":{"number()":"8381f58a"}}`
+const TestFoundryBroadcast = `{"transactions":[{"hash":"0x2222222222222222222222222222222222222222222222222222222222222222","transactionType":"CREATE","contractName":"Counter","contractAddress":"0x1111111111111111111111111111111111111111","function":null,"arguments":null}],"receipts":[{"transactionHash":"0x2222222222222222222222222222222222222222222222222222222222222222","blockNumber":"0x4d2","status":"0x1"}],"timestamp":1700000000,"chain":31337}`
+
+func foundryProjectDir(t *testing.T) string {
+	dir := filet.TmpDir(t, "")
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, "out", "Counter.sol"), os.ModePerm))
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, "out", "Unused.sol"), os.ModePerm))
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, "broadcast", "Counter.s.sol", "31337"), os.ModePerm))
+	filet.File(t, filepath.Join(dir, "foundry.toml"), "[profile.default]\n")
+	filet.File(t, filepath.Join(dir, "out", "Counter.sol", "Counter.json"), TestFoundryArtifact)
+	filet.File(t, filepath.Join(dir, "out", "Unused.sol", "Unused.json"), TestFoundryArtifact)
+	filet.File(t, filepath.Join(dir, "broadcast", "Counter.s.sol", "31337", "run-latest.json"), TestFoundryBroadcast)
+	return dir
+}
+
+func TestGetDeploymentsFromFoundryArtifacts(t *testing.T) {
+	defer filet.CleanUp(t)
+	dir := foundryProjectDir(t)
+
+	deployments, err := GetDeploymentsFrom(filepath.Join(dir, "out"))
+	assert.NoError(t, err)
+	assert.Len(t, deployments, 2)
+
+	counter := deployments["Counter"]
+	assert.Equal(t, common.Address{}, counter.Address)
+	assert.Equal(t, "0x6080", counter.Bytecode)
+	assert.True(t, counter.EvmABI.Methods["number"].Const)
+}
+
+func TestGetDeploymentsFromFoundryProject(t *testing.T) {
+	defer filet.CleanUp(t)
+	dir := foundryProjectDir(t)
+
+	deployments, err := GetDeploymentsFrom(dir)
+	assert.NoError(t, err)
+	assert.Len(t, deployments, 1)
+
+	counter := deployments["Counter"]
+	assert.Equal(t, common.HexToAddress("0x1111111111111111111111111111111111111111"), counter.Address)
+	assert.Equal(t, common.HexToHash("0x2222222222222222222222222222222222222222222222222222222222222222"), counter.TxHash)
+	assert.Equal(t, big.NewInt(1234), counter.CreatedAt)
+
+	_, err = (&Loader{Network: "1"}).Load(dir)
+	assert.EqualError(t, err, "nothing is broadcast to chain 1")
+}
+
+func TestFoundryArtifactsOfProjectOnly(t *testing.T) {
+	defer filet.CleanUp(t)
+	dir := filet.TmpDir(t, "")
+	for _, path := range []string{
+		filepath.Join("Counter.sol", "Counter.json"),
+		filepath.Join("Counter.t.sol", "CounterTest.json"),
+		filepath.Join("Counter.s.sol", "CounterScript.json"),
+		filepath.Join("Test.sol", "Test.json"),
+		filepath.Join("Vm.sol", "Vm.json"),
+		filepath.Join("console.sol", "console.json"),
+		filepath.Join("StdCheats.sol", "StdCheats.json"),
+	} {
+		assert.NoError(t, os.MkdirAll(filepath.Join(dir, filepath.Dir(path)), os.ModePerm))
+		filet.File(t, filepath.Join(dir, path), TestFoundryArtifact)
+	}
+
+	deployments, err := GetDeploymentsFrom(dir)
+	assert.NoError(t, err)
+	assert.Len(t, deployments, 1)
+	assert.Contains(t, deployments, "Counter")
+}
+
+func TestFoundryArtifactsOfSameName(t *testing.T) {
+	defer filet.CleanUp(t)
+	dir := filet.TmpDir(t, "")
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, "Counter.sol"), os.ModePerm))
+	filet.File(t, filepath.Join(dir, "Counter.sol", "Counter.0.8.19.json"), TestFoundryArtifact)
+	filet.File(t, filepath.Join(dir, "Counter.sol", "Counter.0.8.20.json"), TestFoundryArtifact)
+
+	// Compiled by several compiler versions
+	deployments, err := GetDeploymentsFrom(dir)
+	assert.NoError(t, err)
+	assert.Len(t, deployments, 1)
+
+	// Defined in sources of the same name in different directories
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, "legacy", "Counter.sol"), os.ModePerm))
+	filet.File(t, filepath.Join(dir, "legacy", "Counter.sol", "Counter.json"), TestFoundryArtifact)
+	_, err = GetDeploymentsFrom(dir)
+	assert.EqualError(t, err, "contract Counter is defined in both Counter.sol and "+filepath.Join("legacy", "Counter.sol"))
+}
+
+func TestFoundryBroadcastOfUnnamedContract(t *testing.T) {
+	defer filet.CleanUp(t)
+	dir := foundryProjectDir(t)
+	filet.File(t, filepath.Join(dir, "broadcast", "Counter.s.sol", "31337", "run-latest.json"),
+		`{"transactions":[`+
+			`{"hash":"0x3333333333333333333333333333333333333333333333333333333333333333","transactionType":"CREATE","contractName":null,"contractAddress":"0x4444444444444444444444444444444444444444"},`+
+			`{"hash":"0x2222222222222222222222222222222222222222222222222222222222222222","transactionType":"CREATE","contractName":"Counter","contractAddress":"0x1111111111111111111111111111111111111111"}`+
+			`],"receipts":[],"timestamp":1700000000,"chain":31337}`)
+
+	deployments, err := GetDeploymentsFrom(dir)
+	assert.NoError(t, err)
+	assert.Len(t, deployments, 1)
+	assert.Equal(t, common.HexToAddress("0x1111111111111111111111111111111111111111"), deployments["Counter"].Address)
+}
@@ -0,0 +1,221 @@
+package deployment
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+)
+
+// hardhatArtifactFormat prefixes the format of Hardhat artifacts, like hh-sol-artifact-1.
+const hardhatArtifactFormat = "hh-sol-artifact-"
+
+// hardhatChainIdFile holds the chain ID of each hardhat-deploy network directory.
+const hardhatChainIdFile = ".chainId"
+
+// hardhatArtifact is a contract artifact of Hardhat, found in artifacts/<Source>.sol/<Contract>.json.
+type hardhatArtifact struct {
+	Format       string                   `json:"_format"`
+	ContractName string                   `json:"contractName"`
+	SourceName   string                   `json:"sourceName"`
+	ABI          []map[string]interface{} `json:"abi"`
+	Bytecode     string                   `json:"bytecode"`
+}
+
+// hardhatDeployment is a deployment record of hardhat-deploy, found in deployments/<network>/<Contract>.json.
+type hardhatDeployment struct {
+	Address         common.Address           `json:"address"`
+	TransactionHash common.Hash              `json:"transactionHash"`
+	ABI             []map[string]interface{} `json:"abi"`
+	Bytecode        string                   `json:"bytecode"`
+	Receipt         struct {
+		BlockNumber *big.Int `json:"blockNumber"`
+	} `json:"receipt"`
+}
+
+var errFound = errors.New("found")
+
+// isHardhatArtifacts reports whether the directory holds Hardhat artifacts.
+func isHardhatArtifacts(dir string) bool {
+	err := walkArtifacts(dir, func(path string) error {
+		var artifact hardhatArtifact
+		if data, err := ioutil.ReadFile(path); err == nil && json.Unmarshal(data, &artifact) == nil &&
+			strings.HasPrefix(artifact.Format, hardhatArtifactFormat) {
+			return errFound
+		}
+		return filepath.SkipDir
+	})
+	return err == errFound
+}
+
+// isHardhatProjectSource reports whether a source is of the project rooted at root, rather than of
+// a dependency: hardhat itself (hardhat/console.sol), a scoped package like @openzeppelin/contracts,
+// or any other package installed in node_modules.
+func isHardhatProjectSource(root, source string) bool {
+	if strings.HasPrefix(source, "hardhat/") || strings.HasPrefix(source, "@") {
+		return false
+	}
+	_, err := os.Stat(filepath.Join(root, "node_modules", filepath.FromSlash(source)))
+	return err != nil
+}
+
+// fromHardhatArtifacts reads the artifacts of a Hardhat artifacts directory, skipping the ones of dependencies.
+// Artifacts are not deployed, so their address and transaction hash are left zero.
+func (l *Loader) fromHardhatArtifacts(dir string) (Deployments, error) {
+	root := filepath.Dir(filepath.Clean(dir))
+	deployments := make(Deployments)
+	sources := make(map[string]string)
+	err := walkArtifacts(dir, func(path string) error {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		var artifact hardhatArtifact
+		if err := json.Unmarshal(data, &artifact); err != nil {
+			return errors.Wrapf(err, "parse hardhat artifact %s", path)
+		}
+		if !isHardhatProjectSource(root, artifact.SourceName) {
+			return nil
+		}
+		if source, exists := sources[artifact.ContractName]; exists {
+			return errors.Errorf("contract %s is defined in both %s and %s", artifact.ContractName, source, artifact.SourceName)
+		}
+		sources[artifact.ContractName] = artifact.SourceName
+
+		deployments[artifact.ContractName] = Deployment{
+			CreatedAt: new(big.Int),
+			ParsedABI: artifact.ABI,
+			Bytecode:  artifact.Bytecode,
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := deployments.parseABIs(); err != nil {
+		return nil, err
+	}
+	return deployments, nil
+}
+
+func isHardhatNetwork(dir string) bool {
+	_, err := os.Stat(filepath.Join(dir, hardhatChainIdFile))
+	return err == nil
+}
+
+// hardhatNetworks returns the network directories of a hardhat-deploy deployments directory.
+func hardhatNetworks(dir string) ([]string, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var networks []string
+	for _, file := range files {
+		if file.IsDir() && isHardhatNetwork(filepath.Join(dir, file.Name())) {
+			networks = append(networks, file.Name())
+		}
+	}
+	sort.Strings(networks)
+	return networks, nil
+}
+
+// hardhatNetwork resolves the network directory of a hardhat-deploy deployments directory,
+// matching the network either by its name or its chain ID.
+// If no network is given, there must be a single network.
+func hardhatNetwork(dir string, network string) (string, error) {
+	networks, err := hardhatNetworks(dir)
+	if err != nil {
+		return "", err
+	}
+
+	if network == "" {
+		switch len(networks) {
+		case 0:
+			return "", errors.Errorf("no hardhat-deploy network found in %s", dir)
+		case 1:
+			return filepath.Join(dir, networks[0]), nil
+		default:
+			return "", errors.Errorf("hardhat-deploy deployments have several networks (%s), pick one of them", strings.Join(networks, ", "))
+		}
+	}
+
+	for _, name := range networks {
+		chainId, err := ioutil.ReadFile(filepath.Join(dir, name, hardhatChainIdFile))
+		if err != nil {
+			return "", err
+		}
+		if name == network || strings.TrimSpace(string(chainId)) == network {
+			return filepath.Join(dir, name), nil
+		}
+	}
+	return "", errors.Errorf("hardhat-deploy network %s not found in %s", network, dir)
+}
+
+// isHardhatDeployments reports whether the directory holds hardhat-deploy deployments,
+// either of a single network or of several networks.
+func isHardhatDeployments(dir string) bool {
+	if isHardhatNetwork(dir) {
+		return true
+	}
+	networks, err := hardhatNetworks(dir)
+	return err == nil && len(networks) > 0
+}
+
+// fromHardhatDeployments reads the deployments of a hardhat-deploy network directory,
+// or of the network picked from a deployments directory. Like Truffle deployments,
+// only the picked network is bound, so Networks of the deployments are left empty.
+func (l *Loader) fromHardhatDeployments(dir string) (Deployments, error) {
+	if !isHardhatNetwork(dir) {
+		network, err := hardhatNetwork(dir, l.Network)
+		if err != nil {
+			return nil, err
+		}
+		dir = network
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	deployments := make(Deployments)
+	for _, file := range files {
+		// hardhat-deploy keeps its own records in dot-files, like .migrations.json
+		if strings.HasPrefix(filepath.Base(file), ".") {
+			continue
+		}
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+
+		var deployed hardhatDeployment
+		if err := json.Unmarshal(data, &deployed); err != nil {
+			return nil, errors.Wrapf(err, "parse hardhat-deploy deployment %s", file)
+		}
+
+		createdAt := deployed.Receipt.BlockNumber
+		if createdAt == nil {
+			createdAt = new(big.Int)
+		}
+		deployments[strings.TrimSuffix(filepath.Base(file), ".json")] = Deployment{
+			Address:   deployed.Address,
+			TxHash:    deployed.TransactionHash,
+			CreatedAt: createdAt,
+			ParsedABI: deployed.ABI,
+			Bytecode:  deployed.Bytecode,
+		}
+	}
+	if err := deployments.parseABIs(); err != nil {
+		return nil, err
+	}
+	return deployments, nil
+}
@@ -0,0 +1,91 @@
+package deployment
+
+import (
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Flaque/filet"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+const TestHardhatArtifact = `{"_format":"hh-sol-artifact-1","contractName":"Token","sourceName":"contracts/Token.sol","abi":[{"inputs":[],"name":"totalSupply","outputs":[{"internalType":"uint256","name":"","type":"uint256"}],"stateMutability":"view","type":"function"}],"bytecode":"0x6080","deployedBytecode":"0x6080","linkReferences":{},"deployedLinkReferences":{}}`
+const TestHardhatDebug = `{"_format":"hh-sol-dbg-1","buildInfo":"../../build-info/0.json"}`
+const TestHardhatDeployment = `{"address":"0x1111111111111111111111111111111111111111","abi":[{"inputs":[],"name":"totalSupply","outputs":[{"internalType":"uint256","name":"","type":"uint256"}],"stateMutability":"view","type":"function"}],"transactionHash":"0x2222222222222222222222222222222222222222222222222222222222222222","receipt":{"blockNumber":1234,"status":1},"bytecode":"0x6080"}`
+
+func TestGetDeploymentsFromHardhatArtifacts(t *testing.T) {
+	defer filet.CleanUp(t)
+	dir := filet.TmpDir(t, "")
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, "contracts", "Token.sol"), os.ModePerm))
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, "build-info"), os.ModePerm))
+	filet.File(t, filepath.Join(dir, "contracts", "Token.sol", "Token.json"), TestHardhatArtifact)
+	filet.File(t, filepath.Join(dir, "contracts", "Token.sol", "Token.dbg.json"), TestHardhatDebug)
+	filet.File(t, filepath.Join(dir, "build-info", "0.json"), `{}`)
+
+	deployments, err := GetDeploymentsFrom(dir)
+	assert.NoError(t, err)
+	assert.Len(t, deployments, 1)
+
+	token := deployments["Token"]
+	assert.Equal(t, common.Address{}, token.Address)
+	assert.Equal(t, "0x6080", token.Bytecode)
+	assert.Contains(t, token.EvmABI.Methods, "totalSupply")
+}
+
+func TestGetDeploymentsFromHardhatDependencyArtifacts(t *testing.T) {
+	defer filet.CleanUp(t)
+	root := filet.TmpDir(t, "")
+	dir := filepath.Join(root, "artifacts")
+	artifact := func(source, name string) {
+		path := filepath.Join(dir, filepath.FromSlash(source), name+".json")
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), os.ModePerm))
+		content := strings.Replace(TestHardhatArtifact, `"contractName":"Token","sourceName":"contracts/Token.sol"`,
+			`"contractName":"`+name+`","sourceName":"`+source+`"`, 1)
+		filet.File(t, path, content)
+	}
+	artifact("contracts/Token.sol", "Token")
+	artifact("contracts/IERC20.sol", "IERC20")
+	artifact("hardhat/console.sol", "console")
+	artifact("@openzeppelin/contracts/token/ERC20/IERC20.sol", "IERC20")
+	artifact("solmate/src/tokens/ERC20.sol", "ERC20")
+	assert.NoError(t, os.MkdirAll(filepath.Join(root, "node_modules", "solmate", "src", "tokens"), os.ModePerm))
+	filet.File(t, filepath.Join(root, "node_modules", "solmate", "src", "tokens", "ERC20.sol"), "")
+
+	deployments, err := GetDeploymentsFrom(dir)
+	assert.NoError(t, err)
+	assert.Len(t, deployments, 2)
+	assert.Contains(t, deployments, "Token")
+	assert.Contains(t, deployments, "IERC20")
+}
+
+func TestGetDeploymentsFromHardhatDeploy(t *testing.T) {
+	defer filet.CleanUp(t)
+	dir := filet.TmpDir(t, "")
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, "baobab"), os.ModePerm))
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, "localhost"), os.ModePerm))
+	filet.File(t, filepath.Join(dir, "baobab", ".chainId"), "1001")
+	filet.File(t, filepath.Join(dir, "baobab", "Token.json"), TestHardhatDeployment)
+	filet.File(t, filepath.Join(dir, "baobab", ".migrations.json"), `{"deploy_token": 1620000000}`)
+	filet.File(t, filepath.Join(dir, "localhost", ".chainId"), "31337")
+
+	_, err := GetDeploymentsFrom(dir)
+	assert.EqualError(t, err, "hardhat-deploy deployments have several networks (baobab, localhost), pick one of them")
+
+	for _, network := range []string{"baobab", "1001"} {
+		deployments, err := (&Loader{Network: network}).Load(dir)
+		assert.NoError(t, err)
+
+		token := deployments["Token"]
+		assert.Equal(t, common.HexToAddress("0x1111111111111111111111111111111111111111"), token.Address)
+		assert.Equal(t, common.HexToHash("0x2222222222222222222222222222222222222222222222222222222222222222"), token.TxHash)
+		assert.Equal(t, big.NewInt(1234), token.CreatedAt)
+		assert.Contains(t, token.EvmABI.Methods, "totalSupply")
+	}
+
+	deployments, err := GetDeploymentsFrom(filepath.Join(dir, "baobab"))
+	assert.NoError(t, err)
+	assert.Len(t, deployments, 1)
+}
@@ -0,0 +1,77 @@
+package deployment
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Source is the deployments loaded from a single path.
+type Source struct {
+	Path        string
+	Deployments Deployments
+}
+
+// LoadAll loads the deployments of every path and merges them, see Merge.
+func (l *Loader) LoadAll(paths ...string) (Deployments, error) {
+	sources := make([]Source, 0, len(paths))
+	for _, path := range paths {
+		deployments, err := l.Load(path)
+		if err != nil {
+			return nil, errors.Wrapf(err, "load %s", path)
+		}
+		sources = append(sources, Source{Path: path, Deployments: deployments})
+	}
+	return Merge(sources, l.AllowOverride)
+}
+
+// Merge merges the deployments of several sources, later sources taking precedence over earlier ones.
+// A contract defined by several sources must be identical in all of them unless allowOverride is set;
+// every conflicting contract is reported at once otherwise.
+func Merge(sources []Source, allowOverride bool) (Deployments, error) {
+	merged := make(Deployments)
+	definedBy := make(map[string][]string)
+	conflicts := make(map[string]bool)
+	for _, source := range sources {
+		for name, contract := range source.Deployments {
+			if previous, ok := merged[name]; ok && !allowOverride {
+				same, err := identical(previous, contract)
+				if err != nil {
+					return nil, errors.Wrap(err, name)
+				}
+				if !same {
+					conflicts[name] = true
+				}
+			}
+			merged[name] = contract
+			definedBy[name] = append(definedBy[name], source.Path)
+		}
+	}
+	if len(conflicts) == 0 {
+		return merged, nil
+	}
+
+	lines := make([]string, 0, len(conflicts))
+	for name := range conflicts {
+		lines = append(lines, fmt.Sprintf("  %s: %s", name, strings.Join(definedBy[name], ", ")))
+	}
+	sort.Strings(lines)
+	return nil, errors.Errorf("contracts defined differently by several deployments "+
+		"(allow overrides to take the last one):\n%s", strings.Join(lines, "\n"))
+}
+
+func identical(a, b Deployment) (bool, error) {
+	encodedA, err := json.Marshal(a)
+	if err != nil {
+		return false, err
+	}
+	encodedB, err := json.Marshal(b)
+	if err != nil {
+		return false, err
+	}
+	return bytes.Equal(encodedA, encodedB), nil
+}
@@ -0,0 +1,37 @@
+package deployment
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMerge(t *testing.T) {
+	core := Deployments{
+		"ABL":      {Address: common.HexToAddress("0x01")},
+		"Accounts": {Address: common.HexToAddress("0x02")},
+	}
+	partner := Deployments{
+		"ABL":     {Address: common.HexToAddress("0x01")},
+		"Partner": {Address: common.HexToAddress("0x03")},
+	}
+
+	merged, err := Merge([]Source{{"core.json", core}, {"partner.json", partner}}, false)
+	assert.NoError(t, err)
+	assert.Len(t, merged, 3)
+}
+
+func TestMergeConflict(t *testing.T) {
+	core := Deployments{"ABL": {Address: common.HexToAddress("0x01")}}
+	local := Deployments{"ABL": {Address: common.HexToAddress("0x04")}}
+	sources := []Source{{"core.json", core}, {"local.json", local}}
+
+	_, err := Merge(sources, false)
+	assert.EqualError(t, err, "contracts defined differently by several deployments "+
+		"(allow overrides to take the last one):\n  ABL: core.json, local.json")
+
+	merged, err := Merge(sources, true)
+	assert.NoError(t, err)
+	assert.Equal(t, common.HexToAddress("0x04"), merged["ABL"].Address)
+}
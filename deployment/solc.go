@@ -0,0 +1,164 @@
+package deployment
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// solcOutput is the output of solc, either from --combined-json keyed by "<source>:<contract>",
+// or from --standard-json keyed by source and then by contract.
+type solcOutput struct {
+	Contracts map[string]json.RawMessage `json:"contracts"`
+}
+
+// solcContract is a contract of the solc output. Older versions of solc encode
+// the ABI and NatSpec of --combined-json as JSON strings.
+type solcContract struct {
+	ABI     json.RawMessage `json:"abi"`
+	Bin     string          `json:"bin"`
+	DevDoc  json.RawMessage `json:"devdoc"`
+	UserDoc json.RawMessage `json:"userdoc"`
+	EVM     struct {
+		Bytecode struct {
+			Object string `json:"object"`
+		} `json:"bytecode"`
+	} `json:"evm"`
+}
+
+// isSolcOutput reports whether the document is a solc output rather than deployments.
+// A contract named "contracts" in deployments would hold an ABI, while solc contracts
+// are nested in their source.
+func isSolcOutput(document map[string]json.RawMessage) bool {
+	contracts, ok := document["contracts"]
+	if !ok {
+		return false
+	}
+	var entries map[string]json.RawMessage
+	if err := json.Unmarshal(contracts, &entries); err != nil {
+		return false
+	}
+	_, hasABI := entries["abi"]
+	return !hasABI
+}
+
+// unquoteJSON decodes a JSON value encoded as a JSON string.
+func unquoteJSON(value json.RawMessage) json.RawMessage {
+	var text string
+	if err := json.Unmarshal(value, &text); err != nil {
+		return value
+	}
+	return json.RawMessage(text)
+}
+
+func (c solcContract) deployment() (Deployment, error) {
+	var parsedABI []map[string]interface{}
+	if err := json.Unmarshal(unquoteJSON(c.ABI), &parsedABI); err != nil {
+		return Deployment{}, err
+	}
+
+	bytecode := c.Bin
+	if bytecode == "" {
+		bytecode = c.EVM.Bytecode.Object
+	}
+	if bytecode != "" && !strings.HasPrefix(bytecode, "0x") {
+		bytecode = "0x" + bytecode
+	}
+
+	deployment := Deployment{
+		CreatedAt: new(big.Int),
+		ParsedABI: parsedABI,
+		Bytecode:  bytecode,
+	}
+	if len(c.DevDoc) > 0 {
+		deployment.DevDoc = unquoteJSON(c.DevDoc)
+	}
+	if len(c.UserDoc) > 0 {
+		deployment.UserDoc = unquoteJSON(c.UserDoc)
+	}
+	return deployment, nil
+}
+
+// fromSolcOutput reads the contracts compiled by solc. Compiled contracts are not deployed,
+// so their address and transaction hash are left zero.
+func fromSolcOutput(data []byte) (Deployments, error) {
+	var output solcOutput
+	if err := json.Unmarshal(data, &output); err != nil {
+		return nil, err
+	}
+
+	contracts := make(map[string]solcContract)
+	for key, value := range output.Contracts {
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(value, &fields); err != nil {
+			return nil, errors.Wrapf(err, "parse solc contracts of %s", key)
+		}
+
+		// --combined-json, keyed by "<source>:<contract>"
+		if _, ok := fields["abi"]; ok && strings.Contains(key, ":") {
+			var contract solcContract
+			if err := json.Unmarshal(value, &contract); err != nil {
+				return nil, errors.Wrapf(err, "parse solc contract %s", key)
+			}
+			contracts[key] = contract
+			continue
+		}
+
+		// --standard-json
+		var sourceContracts map[string]solcContract
+		if err := json.Unmarshal(value, &sourceContracts); err != nil {
+			return nil, errors.Wrapf(err, "parse solc contracts of %s", key)
+		}
+		for name, contract := range sourceContracts {
+			contracts[key+":"+name] = contract
+		}
+	}
+
+	deployments := make(Deployments)
+	sources := make(map[string]string)
+	for key, contract := range contracts {
+		i := strings.LastIndex(key, ":")
+		source, name := key[:i], key[i+1:]
+		if other, exists := sources[name]; exists {
+			return nil, errors.Errorf("contract %s is defined in both %s and %s", name, other, source)
+		}
+		sources[name] = source
+
+		deployment, err := contract.deployment()
+		if err != nil {
+			return nil, errors.Wrapf(err, "parse abi of solc contract %s", key)
+		}
+		deployments[name] = deployment
+	}
+	if err := deployments.parseABIs(); err != nil {
+		return nil, err
+	}
+	return deployments, nil
+}
+
+// decodeDeployments decodes deployments, or the contracts of a solc output.
+func decodeDeployments(data []byte) (Deployments, error) {
+	var document map[string]json.RawMessage
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, err
+	}
+	if isSolcOutput(document) {
+		return fromSolcOutput(data)
+	}
+
+	if err := Validate(data); err != nil {
+		return nil, err
+	}
+
+	deployments := make(Deployments)
+	if err := json.NewDecoder(bytes.NewReader(data)).Decode(&deployments); err != nil {
+		return nil, err
+	}
+	if err := deployments.parseABIs(); err != nil {
+		return nil, err
+	}
+	return deployments, nil
+}
@@ -0,0 +1,43 @@
+package deployment
+
+import (
+	"testing"
+
+	"github.com/Flaque/filet"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+// abi and NatSpec encoded as JSON strings, as solc < 0.8 does
+const TestSolcCombinedJSON = `{"contracts":{"contracts/Migrations.sol:Migrations":{"abi":"[{\"constant\":true,\"inputs\":[],\"name\":\"owner\",\"outputs\":[{\"name\":\"\",\"type\":\"address\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"}]","bin":"6080","devdoc":"{\"methods\":{}}","userdoc":"{\"methods\":{}}"}},"version":"0.5.11"}`
+const TestSolcStandardJSON = `{"contracts":{"contracts/Counter.sol":{"Counter":{"abi":[{"inputs":[],"name":"number","outputs":[{"internalType":"uint256","name":"","type":"uint256"}],"stateMutability":"view","type":"function"}],"devdoc":{"kind":"dev","methods":{"number()":{"details":"current number"}}},"userdoc":{"kind":"user","methods":{}},"evm":{"bytecode":{"object":"6080"}}}}},"sources":{"contracts/Counter.sol":{"id":0}}}`
+
+func TestGetDeploymentsFromSolcCombinedJSON(t *testing.T) {
+	defer filet.CleanUp(t)
+	filet.File(t, TestDeploymentPath, TestSolcCombinedJSON)
+
+	deployments, err := GetDeploymentsFrom(TestDeploymentPath)
+	assert.NoError(t, err)
+
+	migrations, ok := deployments["Migrations"]
+	assert.True(t, ok)
+	assert.Equal(t, common.Address{}, migrations.Address)
+	assert.Equal(t, "0x6080", migrations.Bytecode)
+	assert.JSONEq(t, `{"methods":{}}`, string(migrations.DevDoc))
+	assert.Contains(t, migrations.EvmABI.Methods, "owner")
+}
+
+func TestGetDeploymentsFromSolcStandardJSON(t *testing.T) {
+	defer filet.CleanUp(t)
+	filet.File(t, TestDeploymentPath, TestSolcStandardJSON)
+
+	deployments, err := GetDeploymentsFrom(TestDeploymentPath)
+	assert.NoError(t, err)
+
+	counter, ok := deployments["Counter"]
+	assert.True(t, ok)
+	assert.Equal(t, common.Address{}, counter.Address)
+	assert.Equal(t, "0x6080", counter.Bytecode)
+	assert.JSONEq(t, `{"kind":"dev","methods":{"number()":{"details":"current number"}}}`, string(counter.DevDoc))
+	assert.True(t, counter.EvmABI.Methods["number"].Const)
+}
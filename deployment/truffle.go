@@ -0,0 +1,117 @@
+package deployment
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+)
+
+// truffleArtifact is a contract artifact of Truffle, found in build/contracts.
+type truffleArtifact struct {
+	ContractName string                   `json:"contractName"`
+	ABI          []map[string]interface{} `json:"abi"`
+	Bytecode     string                   `json:"bytecode"`
+	Networks     map[string]struct {
+		Address         common.Address `json:"address"`
+		TransactionHash common.Hash    `json:"transactionHash"`
+	} `json:"networks"`
+}
+
+func readTruffleArtifacts(dir string) ([]truffleArtifact, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	artifacts := make([]truffleArtifact, 0, len(files))
+	for _, file := range files {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+
+		var artifact truffleArtifact
+		if err := json.Unmarshal(data, &artifact); err != nil {
+			return nil, errors.Wrapf(err, "parse truffle artifact %s", file)
+		}
+		if artifact.ContractName == "" {
+			artifact.ContractName = strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+		}
+		artifacts = append(artifacts, artifact)
+	}
+	return artifacts, nil
+}
+
+// truffleNetwork resolves the network of the artifacts. If no network is given,
+// the artifacts must be deployed to a single network.
+func truffleNetwork(artifacts []truffleArtifact, network string) (string, error) {
+	if network != "" {
+		return network, nil
+	}
+
+	networks := make(map[string]bool)
+	for _, artifact := range artifacts {
+		for id := range artifact.Networks {
+			networks[id] = true
+		}
+	}
+
+	ids := make([]string, 0, len(networks))
+	for id := range networks {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	switch len(ids) {
+	case 0:
+		return "", errors.New("truffle artifacts are not deployed to any network")
+	case 1:
+		return ids[0], nil
+	default:
+		return "", errors.Errorf("truffle artifacts are deployed to several networks (%s), pick one of them", strings.Join(ids, ", "))
+	}
+}
+
+// fromTruffle reads the artifacts of a Truffle build/contracts directory.
+// Contracts not deployed to the network are skipped. Networks of the deployments are
+// left empty, as Truffle keys artifacts by network IDs, which may differ from chain IDs.
+func (l *Loader) fromTruffle(dir string) (Deployments, error) {
+	artifacts, err := readTruffleArtifacts(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	network, err := truffleNetwork(artifacts, l.Network)
+	if err != nil {
+		return nil, err
+	}
+
+	deployments := make(Deployments)
+	for _, artifact := range artifacts {
+		deployed, ok := artifact.Networks[network]
+		if !ok {
+			continue
+		}
+
+		deployments[artifact.ContractName] = Deployment{
+			Address:   deployed.Address,
+			TxHash:    deployed.TransactionHash,
+			CreatedAt: new(big.Int),
+			ParsedABI: artifact.ABI,
+			Bytecode:  artifact.Bytecode,
+		}
+	}
+	if len(deployments) == 0 {
+		return nil, errors.Errorf("no truffle artifact is deployed to network %s", network)
+	}
+	if err := deployments.parseABIs(); err != nil {
+		return nil, err
+	}
+	return deployments, nil
+}
@@ -0,0 +1,55 @@
+package deployment
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/Flaque/filet"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+const TestTruffleMigrations = `{"contractName":"Migrations","abi":[{"constant":true,"inputs":[],"name":"owner","outputs":[{"name":"","type":"address"}],"payable":false,"stateMutability":"view","type":"function"}],"bytecode":"0x6080","networks":{"5777":{"address":"0x1111111111111111111111111111111111111111","transactionHash":"0x2222222222222222222222222222222222222222222222222222222222222222"},"1001":{"address":"0x3333333333333333333333333333333333333333","transactionHash":"0x4444444444444444444444444444444444444444444444444444444444444444"}}}`
+const TestTruffleOwnable = `{"contractName":"Ownable","abi":[{"constant":true,"inputs":[],"name":"owner","outputs":[{"name":"","type":"address"}],"payable":false,"stateMutability":"view","type":"function"}],"bytecode":"0x","networks":{}}`
+
+func truffleBuildDir(t *testing.T) string {
+	dir := filet.TmpDir(t, "")
+	filet.File(t, filepath.Join(dir, "Migrations.json"), TestTruffleMigrations)
+	filet.File(t, filepath.Join(dir, "Ownable.json"), TestTruffleOwnable)
+	return dir
+}
+
+func TestGetDeploymentsFromTruffle(t *testing.T) {
+	defer filet.CleanUp(t)
+	dir := truffleBuildDir(t)
+
+	deployments, err := (&Loader{Network: "1001"}).Load(dir)
+	assert.NoError(t, err)
+
+	migrations, ok := deployments["Migrations"]
+	assert.True(t, ok)
+	assert.Equal(t, common.HexToAddress("0x3333333333333333333333333333333333333333"), migrations.Address)
+	assert.Equal(t, common.HexToHash("0x4444444444444444444444444444444444444444444444444444444444444444"), migrations.TxHash)
+	assert.Equal(t, "0x6080", migrations.Bytecode)
+	assert.Contains(t, migrations.EvmABI.Methods, "owner")
+
+	// not deployed to any network
+	_, ok = deployments["Ownable"]
+	assert.False(t, ok)
+}
+
+func TestGetDeploymentsFromTruffleSeveralNetworks(t *testing.T) {
+	defer filet.CleanUp(t)
+	dir := truffleBuildDir(t)
+
+	_, err := GetDeploymentsFrom(dir)
+	assert.EqualError(t, err, "truffle artifacts are deployed to several networks (1001, 5777), pick one of them")
+}
+
+func TestGetDeploymentsFromTruffleUnknownNetwork(t *testing.T) {
+	defer filet.CleanUp(t)
+	dir := truffleBuildDir(t)
+
+	_, err := (&Loader{Network: "1"}).Load(dir)
+	assert.EqualError(t, err, "no truffle artifact is deployed to network 1")
+}
@@ -0,0 +1,353 @@
+package deployment
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+)
+
+// Problem is a single invalid value of a deployment file.
+type Problem struct {
+	Contract string // Name of the contract holding the value
+	Entry    int    // Index of the ABI entry holding the value, -1 outside of the ABI
+	Path     string // JSON path of the value, such as $.Migrations.abi[0].inputs[1].type
+	Message  string
+}
+
+func (p Problem) String() string {
+	if p.Entry >= 0 {
+		return fmt.Sprintf("%s: abi entry %d: %s: %s", p.Contract, p.Entry, p.Path, p.Message)
+	}
+	return fmt.Sprintf("%s: %s: %s", p.Contract, p.Path, p.Message)
+}
+
+// ValidationError lists every problem found in a deployment file.
+type ValidationError struct {
+	Problems []Problem
+}
+
+func (e *ValidationError) Error() string {
+	lines := make([]string, 0, len(e.Problems)+1)
+	lines = append(lines, fmt.Sprintf("invalid deployment: %d problems", len(e.Problems)))
+	for _, p := range e.Problems {
+		lines = append(lines, "  "+p.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+var (
+	addressPattern = regexp.MustCompile(`^0x[0-9a-fA-F]{40}$`)
+	hashPattern    = regexp.MustCompile(`^0x[0-9a-fA-F]{64}$`)
+	namePattern    = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+	sizedPattern   = regexp.MustCompile(`^(u?int|bytes)([0-9]+)(\[|$)`)
+
+	abiEntryTypes       = []string{"function", "constructor", "fallback", "receive", "event", "error"}
+	abiStateMutabilites = []string{"pure", "view", "nonpayable", "payable"}
+)
+
+// Validate checks every contract of a deployment file for a valid address, transaction hash,
+// numeric created_at and well-formed ABI entries, as well as the ones of its networks.
+// Every problem found is reported at once by a *ValidationError.
+func Validate(data []byte) error {
+	var contracts map[string]json.RawMessage
+	if err := json.Unmarshal(data, &contracts); err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(contracts))
+	for name := range contracts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	v := new(validator)
+	for _, name := range names {
+		v.contract = name
+		v.entry = -1
+		v.validateContract(jsonKey("$", name), contracts[name])
+	}
+	if len(v.problems) > 0 {
+		return &ValidationError{Problems: v.problems}
+	}
+	return nil
+}
+
+// validator accumulates the problems of a deployment file.
+type validator struct {
+	contract string
+	entry    int
+	problems []Problem
+}
+
+func (v *validator) report(path, format string, args ...interface{}) {
+	v.problems = append(v.problems, Problem{
+		Contract: v.contract,
+		Entry:    v.entry,
+		Path:     path,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+func (v *validator) validateContract(path string, data json.RawMessage) {
+	fields, ok := v.object(path, data)
+	if !ok {
+		return
+	}
+	v.validateLocation(path, fields)
+
+	if networks, ok := fields["networks"]; ok {
+		networksPath := jsonKey(path, "networks")
+		entries, _ := v.object(networksPath, networks)
+		for _, chainID := range sortedKeys(entries) {
+			networkPath := jsonKey(networksPath, chainID)
+			if id, ok := new(big.Int).SetString(chainID, 10); !ok || id.Sign() < 0 {
+				v.report(networkPath, "chain ID %q is not a decimal number", chainID)
+			}
+			if network, ok := v.object(networkPath, entries[chainID]); ok {
+				v.validateLocation(networkPath, network)
+			}
+		}
+	}
+
+	rawABI, ok := fields["abi"]
+	if !ok {
+		v.report(path, "abi is missing")
+		return
+	}
+	abiPath := jsonKey(path, "abi")
+	entries, ok := v.array(abiPath, rawABI)
+	if !ok {
+		return
+	}
+	for i, entry := range entries {
+		v.entry = i
+		v.validateABIEntry(fmt.Sprintf("%s[%d]", abiPath, i), entry)
+	}
+	v.entry = -1
+}
+
+// validateLocation checks the address, transaction hash and creation block of a contract or network.
+func (v *validator) validateLocation(path string, fields map[string]json.RawMessage) {
+	if address, ok := fields["address"]; ok {
+		v.pattern(jsonKey(path, "address"), address, addressPattern, "an address")
+	}
+	if txHash, ok := fields["tx_hash"]; ok {
+		v.pattern(jsonKey(path, "tx_hash"), txHash, hashPattern, "a transaction hash")
+	}
+	if createdAt, ok := fields["created_at"]; ok && !isNull(createdAt) {
+		if _, ok := new(big.Int).SetString(string(bytes.TrimSpace(createdAt)), 10); !ok {
+			v.report(jsonKey(path, "created_at"), "expected a block number, got %s", createdAt)
+		}
+	}
+}
+
+func (v *validator) validateABIEntry(path string, data json.RawMessage) {
+	fields, ok := v.object(path, data)
+	if !ok {
+		return
+	}
+
+	entryType := "function"
+	if raw, ok := fields["type"]; ok {
+		if v.str(jsonKey(path, "type"), raw, &entryType) && !contains(abiEntryTypes, entryType) {
+			v.report(jsonKey(path, "type"), "unknown entry type %q", entryType)
+		}
+	}
+
+	switch entryType {
+	case "function", "event", "error":
+		var name string
+		if raw, ok := fields["name"]; !ok {
+			v.report(path, "name of %s is missing", entryType)
+		} else if v.str(jsonKey(path, "name"), raw, &name) && !namePattern.MatchString(name) {
+			v.report(jsonKey(path, "name"), "invalid name %q", name)
+		}
+	}
+
+	for _, flag := range []string{"constant", "payable", "anonymous"} {
+		if raw, ok := fields[flag]; ok {
+			v.boolean(jsonKey(path, flag), raw)
+		}
+	}
+	if raw, ok := fields["stateMutability"]; ok {
+		var mutability string
+		if v.str(jsonKey(path, "stateMutability"), raw, &mutability) && !contains(abiStateMutabilites, mutability) {
+			v.report(jsonKey(path, "stateMutability"), "unknown state mutability %q", mutability)
+		}
+	}
+
+	for _, list := range []string{"inputs", "outputs"} {
+		raw, ok := fields[list]
+		if !ok {
+			continue
+		}
+		params, ok := v.array(jsonKey(path, list), raw)
+		if !ok {
+			continue
+		}
+		for i, param := range params {
+			v.validateParam(fmt.Sprintf("%s[%d]", jsonKey(path, list), i), param)
+		}
+	}
+}
+
+// validateParam checks an input or output parameter, and the components of tuples.
+func (v *validator) validateParam(path string, data json.RawMessage) (abi.ArgumentMarshaling, bool) {
+	var param abi.ArgumentMarshaling
+	fields, ok := v.object(path, data)
+	if !ok {
+		return param, false
+	}
+
+	valid := true
+	if raw, ok := fields["name"]; ok {
+		valid = v.str(jsonKey(path, "name"), raw, &param.Name) && valid
+	}
+	if raw, ok := fields["indexed"]; ok {
+		v.boolean(jsonKey(path, "indexed"), raw)
+	}
+	if raw, ok := fields["components"]; ok {
+		components, ok := v.array(jsonKey(path, "components"), raw)
+		for i, component := range components {
+			c, ok := v.validateParam(fmt.Sprintf("%s[%d]", jsonKey(path, "components"), i), component)
+			valid = ok && valid
+			param.Components = append(param.Components, c)
+		}
+		valid = ok && valid
+	}
+
+	raw, ok := fields["type"]
+	if !ok {
+		v.report(path, "type is missing")
+		return param, false
+	}
+	if !v.str(jsonKey(path, "type"), raw, &param.Type) {
+		return param, false
+	}
+	if valid {
+		if err := checkSize(param.Type); err != nil {
+			v.report(jsonKey(path, "type"), "invalid type %q: %v", param.Type, err)
+			return param, false
+		}
+		if _, err := abi.NewType(param.Type, param.Components); err != nil {
+			v.report(jsonKey(path, "type"), "invalid type %q: %v", param.Type, err)
+			return param, false
+		}
+	}
+	return param, valid
+}
+
+func (v *validator) object(path string, data json.RawMessage) (map[string]json.RawMessage, bool) {
+	var fields map[string]json.RawMessage
+	if kind(data) != '{' || json.Unmarshal(data, &fields) != nil {
+		v.report(path, "expected an object, got %s", describe(data))
+		return nil, false
+	}
+	return fields, true
+}
+
+func (v *validator) array(path string, data json.RawMessage) ([]json.RawMessage, bool) {
+	var items []json.RawMessage
+	if kind(data) != '[' || json.Unmarshal(data, &items) != nil {
+		v.report(path, "expected an array, got %s", describe(data))
+		return nil, false
+	}
+	return items, true
+}
+
+func (v *validator) str(path string, data json.RawMessage, s *string) bool {
+	if kind(data) != '"' || json.Unmarshal(data, s) != nil {
+		v.report(path, "expected a string, got %s", describe(data))
+		return false
+	}
+	return true
+}
+
+func (v *validator) boolean(path string, data json.RawMessage) {
+	var b bool
+	if json.Unmarshal(data, &b) != nil {
+		v.report(path, "expected a boolean, got %s", describe(data))
+	}
+}
+
+func (v *validator) pattern(path string, data json.RawMessage, pattern *regexp.Regexp, expected string) {
+	var s string
+	if !v.str(path, data, &s) {
+		return
+	}
+	if !pattern.MatchString(s) {
+		v.report(path, "expected %s, got %q", expected, s)
+	}
+}
+
+// checkSize checks the size of sized integer and byte array types, which abi.NewType leaves unchecked.
+func checkSize(typ string) error {
+	match := sizedPattern.FindStringSubmatch(typ)
+	if match == nil {
+		return nil
+	}
+	size, _ := strconv.Atoi(match[2])
+	if match[1] == "bytes" {
+		if size < 1 || size > 32 {
+			return fmt.Errorf("size of bytes must be 1 to 32, got %d", size)
+		}
+	} else if size < 8 || size > 256 || size%8 != 0 {
+		return fmt.Errorf("size of %s must be a multiple of 8 up to 256, got %d", match[1], size)
+	}
+	return nil
+}
+
+// kind returns the first character of a JSON value, telling its type.
+func kind(data json.RawMessage) byte {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return 0
+	}
+	return data[0]
+}
+
+func isNull(data json.RawMessage) bool {
+	return bytes.Equal(bytes.TrimSpace(data), []byte("null"))
+}
+
+// describe shortens a JSON value for problem messages.
+func describe(data json.RawMessage) string {
+	s := string(bytes.TrimSpace(data))
+	if len(s) > 32 {
+		s = s[:29] + "..."
+	}
+	return s
+}
+
+// jsonKey appends a key to a JSON path, quoting it unless it is an identifier.
+func jsonKey(path, key string) string {
+	if namePattern.MatchString(key) {
+		return path + "." + key
+	}
+	return path + "[" + strconv.Quote(key) + "]"
+}
+
+func sortedKeys(m map[string]json.RawMessage) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
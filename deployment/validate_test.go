@@ -0,0 +1,50 @@
+package deployment
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const TestInvalidDeployment = `{
+  "Exchange": {
+    "address": "0x1234",
+    "tx_hash": "0x2222222222222222222222222222222222222222222222222222222222222222",
+    "created_at": "123",
+    "abi": [
+      {"type": "function", "name": "settle", "inputs": [{"name": "offer", "type": "tuple", "components": [{"name": "id", "type": "bytes8"}, {"name": "amount"}]}], "outputs": []},
+      {"type": "event", "name": "Settled", "inputs": [{"name": "id", "type": "uint257", "indexed": true}]},
+      {"type": "function", "constant": "yes", "inputs": []}
+    ]
+  },
+  "Migrations": {
+    "networks": {"baobab": {"address": "0x1111111111111111111111111111111111111111"}, "8217": {"created_at": 1.5}},
+    "abi": {}
+  },
+  "Valid": {"abi": [{"type": "constructor", "inputs": [{"name": "owner", "type": "address"}]}]}
+}`
+
+func TestValidate(t *testing.T) {
+	err := Validate([]byte(TestInvalidDeployment))
+	if !assert.IsType(t, &ValidationError{}, err) {
+		return
+	}
+
+	problems := err.(*ValidationError).Problems
+	assert.Equal(t, []Problem{
+		{Contract: "Exchange", Entry: -1, Path: "$.Exchange.address", Message: `expected an address, got "0x1234"`},
+		{Contract: "Exchange", Entry: -1, Path: "$.Exchange.created_at", Message: `expected a block number, got "123"`},
+		{Contract: "Exchange", Entry: 0, Path: "$.Exchange.abi[0].inputs[0].components[1]", Message: "type is missing"},
+		{Contract: "Exchange", Entry: 1, Path: "$.Exchange.abi[1].inputs[0].type", Message: `invalid type "uint257": size of uint must be a multiple of 8 up to 256, got 257`},
+		{Contract: "Exchange", Entry: 2, Path: "$.Exchange.abi[2]", Message: "name of function is missing"},
+		{Contract: "Exchange", Entry: 2, Path: "$.Exchange.abi[2].constant", Message: `expected a boolean, got "yes"`},
+		{Contract: "Migrations", Entry: -1, Path: `$.Migrations.networks["8217"].created_at`, Message: "expected a block number, got 1.5"},
+		{Contract: "Migrations", Entry: -1, Path: "$.Migrations.networks.baobab", Message: `chain ID "baobab" is not a decimal number`},
+		{Contract: "Migrations", Entry: -1, Path: "$.Migrations.abi", Message: "expected an array, got {}"},
+	}, problems)
+}
+
+func TestValidateValid(t *testing.T) {
+	assert.NoError(t, Validate([]byte(TestDeployment)))
+	assert.NoError(t, Validate([]byte(TestMultiNetworkDeployment)))
+}
@@ -40,8 +40,8 @@ func (c *contract) parseContract(contractAbi abi.ABI) {
 
 	methodIndex := 0
 	for methodName, methodInfo := range contractAbi.Methods {
-		inputMessage := fmt.Sprintf("Request%s", utils.Capitalize(methodName))
-		outputMessage := fmt.Sprintf("Response%s", utils.Capitalize(methodName))
+		inputMessage := fmt.Sprintf("Request%s", utils.Capitalise(methodName))
+		outputMessage := fmt.Sprintf("Response%s", utils.Capitalise(methodName))
 
 		if len(methodInfo.Inputs) == 0 {
 			inputMessage = EmptyMessage
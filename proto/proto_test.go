@@ -1,6 +1,7 @@
 package proto
 
 import (
+	"net"
 	"os"
 	"path/filepath"
 	"testing"
@@ -20,8 +21,6 @@ func TestGenerateBind(t *testing.T) {
 	tmpName := filepath.Join(dirName, TestDeploymentPath)
 	filet.File(t, tmpName, TestDeployment)
 
-	assert.NoError(t, os.Chdir(".."))
-
 	deployments, err := deployment.GetDeploymentsFrom(tmpName)
 	assert.NoError(t, err)
 	assert.NoError(t, GenerateBind(dirName, deployments, Options{}))
@@ -30,8 +29,14 @@ func TestGenerateBind(t *testing.T) {
 }
 
 func TestGenerateBind_Airbloc(t *testing.T) {
-	assert.NoError(t, os.Chdir(".."))
+	conn, err := net.Dial("tcp", "localhost:8500")
+	if err != nil {
+		t.Skip("airbloc deployment server is not running on localhost:8500")
+	}
+	conn.Close()
+
 	assert.NoError(t, os.RemoveAll("./test/proto"))
+	defer os.RemoveAll("./test")
 
 	deployments, err := deployment.GetDeploymentsFrom("http://localhost:8500")
 	assert.NoError(t, err)
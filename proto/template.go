@@ -2,16 +2,22 @@ package proto
 
 import (
 	"bytes"
-	"html/template"
 	"io"
 	"os"
+	"strings"
+	"text/template"
+
+	"github.com/airbloc/solgen/proto/templates"
 )
 
-const templatePath = "./proto/templates/*"
+var bindTemplate = template.Must(template.New("Bind").Parse(strings.Join([]string{
+	templates.Bind,
+	templates.Message,
+	templates.Service,
+}, "\n")))
 
 func render(writer io.Writer, c contract) error {
-	tmpl := template.Must(template.New("Bind").ParseGlob(templatePath))
-	if err := tmpl.ExecuteTemplate(writer, "Bind", c); err != nil {
+	if err := bindTemplate.ExecuteTemplate(writer, "Bind", c); err != nil {
 		return err
 	}
 	return nil
@@ -26,17 +32,10 @@ func Render(c contract) ([]byte, error) {
 }
 
 func RenderFile(path string, c contract) error {
-	var out *os.File
-	if _, err := os.Stat(path); os.IsNotExist(err) {
-		out, err = os.Create(path)
-		if err != nil {
-			return err
-		}
-	} else {
-		out, err = os.OpenFile(path, os.O_WRONLY, os.ModePerm)
-		if err != nil {
-			return err
-		}
+	out, err := os.Create(path)
+	if err != nil {
+		return err
 	}
+	defer out.Close()
 	return render(out, c)
 }
@@ -0,0 +1,17 @@
+package templates
+
+const Bind = `{{define "Bind"}}
+// Auto Generated. But feel free to EDIT!
+syntax = "proto3";
+package airbloc.{{.PackageName}};
+
+import "google/protobuf/empty.proto";
+
+{{template "Service" .}}
+{{template "Message" .}}
+{{end}}`
+
+const Message = `{{define "Message"}}{{range .Messages}}{{.PrintMessage}}
+{{end}}{{end}}`
+
+const Service = `{{define "Service"}}{{range .Services}}{{.PrintService}}{{end}}{{end}}`
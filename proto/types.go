@@ -28,17 +28,17 @@ func parseType(index int, a abi.Argument, typeOptions map[string]string) (msg *m
 		msg = &message{
 			Args:    make([]argument, len(argType.TupleElems)),
 			Comment: argType.Type.String(),
-			Name:    StructPrefix + utils.Capitalize(argType.Kind.String()) + strconv.Itoa(index),
+			Name:    StructPrefix + utils.Capitalise(argType.Kind.String()) + strconv.Itoa(index),
 		}
 
 		if !strings.HasPrefix(argName, "struct") {
 			arg.Name = utils.Decapitalise(argName)
-			msg.Name = utils.Capitalize(argName)
+			msg.Name = utils.Capitalise(argName)
 		}
 
 		if name, ok := typeOptions[argType.String()]; ok {
 			arg.Name = utils.Decapitalise(name)
-			msg.Name = utils.Capitalize(name)
+			msg.Name = utils.Capitalise(name)
 		}
 
 		args := make([]abi.Argument, len(argType.TupleElems))